@@ -11,29 +11,39 @@ import (
 	"fmt"
 	"github.com/jonhanks/Go-SDL/sdl"
 	"github.com/jonhanks/Go-SDL/ttf"
+	"math"
 	"math/rand"
 	"os"
 	"runtime"
+	"strings"
 	//"runtime/pprof"
 	//"strconv"
 	"time"
 )
 
 const (
-	// screen size
+	// the screen size the game is laid out for, see frameW
 	WIDTH  = 1024
 	HEIGHT = 768
 
-	// width of the blocks
+	// width of the blocks and how far they step, on a WIDTH x HEIGHT screen
 	RWIDTH  = 20
 	RHEIGHT = 20
 	STEP    = 15.0
 	// step size increase per button press
 	BIGMULTIPLIER = 40
 	HATMULTIPLIER = 0.4
+	// marker velocity with the stick pushed all the way
+	FULL_SPEED = 0.5
 
 	// goals/targets
 	GOALS_SRC = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	// the most updates run to catch up before a frame is drawn
+	MAX_CATCHUP = 5
+
+	// background color, unless the theme gives another
+	BACKGROUND = uint32(0x00202020)
 )
 
 // Drawables know how to draw themselves and provide bounding rectangles for collision detection.
@@ -50,23 +60,83 @@ type Goal struct {
 	Hidden  bool         // should this be drawn
 	X, Y    int          // location
 	W, H    int          // size
+	VX, VY  float64      // direction a moving goal drifts in, see moveGoals
+	fx, fy  float64      // exact position of a moving goal
+	full    *sdl.Surface // the goal at its full size, when it has been shrunk
+
+	contrast   *sdl.Surface   // the goal in the high contrast theme
+	contrastOf *sdl.Surface   // the Surface contrast was made from
+	pulse      []*sdl.Surface // the goal scaled for each step of its pulse, made when first needed
+	pulseOf    *sdl.Surface   // the Surface pulse was made from
 }
 
-// Create a new Goal object.  Rendering the given rune with the given font
-func NewGoal(f *ttf.Font, ch rune, order int) *Goal {
+// Create a new Goal object.  Rendering the given text with the given font, with a drop shadow if
+// config.ShadowOffset is set
+func NewGoal(f *ttf.Font, text string, order int) *Goal {
 	g := &Goal{}
-	g.Text = string(ch)
+	g.Text = text
 	g.Order = order
-	g.Surface = ttf.RenderUTF8_Blended(f, g.Text, sdl.Color{255, 255, 255, 0})
+	g.Surface = ttf.RenderUTF8_Blended(f, g.Text, config.GoalColor)
+	if config.ShadowOffset > 0 {
+		shadow := ttf.RenderUTF8_Blended(f, g.Text, config.ShadowColor)
+		if composed := withShadow(g.Surface, shadow, config.ShadowOffset); composed != nil {
+			g.Surface.Free()
+			g.Surface = composed
+		}
+		shadow.Free()
+	}
 	g.W, g.H = int(g.Surface.W), int(g.Surface.H)
 	return g
 }
 
+// Build a goal for each character of src, placed at random on the screen.  Consecutive goals keep
+// away from each other as set by config.AvoidDistance.
+func buildGoals(f *ttf.Font, src string, rng *rand.Rand) []*Goal {
+	var texts []string
+	for _, ch := range src {
+		texts = append(texts, string(ch))
+	}
+	return buildTextGoals(f, texts, rng)
+}
+
+// Build a goal for each of the texts, which can be more than one character long, placed at random
+func buildTextGoals(f *ttf.Font, texts []string, rng *rand.Rand) []*Goal {
+	goals := make([]*Goal, len(texts))
+	var recent placeHistory
+	for i, text := range texts {
+		goals[i] = NewGoal(f, text, i)
+		placeRandom(goals[i], &recent, rng)
+		goals[i].Hidden = false
+	}
+	return goals
+}
+
+// Release the rendered text of the Goal
+func (g *Goal) Free() {
+	if g.full != nil && g.full != g.Surface {
+		g.full.Free()
+	}
+	g.full = nil
+	g.freePulse()
+	if g.contrast != nil {
+		g.contrast.Free()
+		g.contrast, g.contrastOf = nil, nil
+	}
+	if g.Surface != nil {
+		g.Surface.Free()
+		g.Surface = nil
+	}
+}
+
 // Draw the Goal object on the given surface
-func (g Goal) Draw(screen *sdl.Surface) {
+func (g *Goal) Draw(screen *sdl.Surface) {
 	if g.Hidden || g.Surface == nil {
 		return
 	}
+	if config.HighContrast {
+		g.drawContrast(screen)
+		return
+	}
 	screen.Blit(g.Rect(), g.Surface, nil)
 }
 
@@ -78,12 +148,54 @@ func (g Goal) Rect() *sdl.Rect {
 // A Marker is the object tracking the joystick location.
 type Marker struct {
 	Joystick            *sdl.Joystick // the joystick
-	X, Y                int           // position 
+	X, Y                int           // position
+	PrevX, PrevY        int           // position before the last update, used to smooth drawing
 	Vax, Vay            float32       // velocity due to the button pad
+	targetX, targetY    float32       // the stick position Vax and Vay are smoothed towards
 	Vhx, Vhy            float32       // velocity due to the hat
+	velX, velY          float32       // velocity with the inertia physics, see accelerate
+	fracX, fracY        float32       // the part of a pixel moved but not yet added to X and Y
+	dwellGoal           *Goal         // the goal the marker is on
+	dwell               int           // for how many updates in a row
+	touched             *Goal         // the goal counted in simon says, until the marker leaves it
+	Paint               int           // index into paintColors of the color painted in paint mode
+	Triggers            [2]float32    // how far the left and right triggers are squeezed, 0 to 1
 	Color               uint32
-	Big                 int  // how many buttons are pressed
-	lastZero, last2Zero bool // I cannot remember what this is used for
+	Sprite              *sdl.Surface // image drawn instead of a square of Color, nil for the square
+	cache               *markerCache // the marker's body as last drawn, shared by its copies
+	Pattern             int          // the PATTERN_* drawn over the color with -patterns, to tell players apart
+	Bounds              sdl.Rect     // the area the marker is kept in, the whole screen if empty
+	Walls               *Maze        // walls the marker cannot pass through, nil for none
+	Big                 int          // how many buttons are pressed
+	Held                int          // how many buttons are currently held down
+	Presses             int          // button presses this round
+	buttons             uint64       // bit mask of the buttons currently held
+	Score               int          // points scored this session
+	Distance            float64      // total distance travelled (in pixels) this session
+	trail               Trail        // where the marker has recently been, with -trail
+	Collecting          bool         // the collect button is held
+	hasCollect          bool         // the player has a collect button
+	collectPressed      bool         // the collect button was pressed since the last update
+	lastZero, last2Zero bool         // I cannot remember what this is used for
+}
+
+// Get how far the marker moves in one update at its current velocity, in whole pixels.  The part of a
+// pixel left over is kept for the next update, so slow movement is not rounded away to nothing.
+func (m *Marker) Step() (dx, dy int) {
+	boost := m.Boost()
+	step := float32(config.Speed * frameScale)
+	var fx, fy float32
+	if config.Inertia {
+		fx, fy = step*m.velX*boost, step*m.velY*boost
+	} else {
+		fx = step*m.Vax*boost + step*m.Vhx*HATMULTIPLIER*boost
+		fy = step*m.Vay*boost + step*m.Vhy*HATMULTIPLIER*boost
+	}
+	fx += m.fracX
+	fy += m.fracY
+	dx, dy = int(fx), int(fy)
+	m.fracX, m.fracY = fx-float32(dx), fy-float32(dy)
+	return dx, dy
 }
 
 // Update the markers position
@@ -91,22 +203,23 @@ func (m *Marker) Update() {
 	if m == nil {
 		return
 	}
-	m.X += int(STEP*m.Vax) + int(STEP*m.Vhx*HATMULTIPLIER)
-	m.Y += int(STEP*m.Vay) + int(STEP*m.Vhy*HATMULTIPLIER)
-	if m.X < 0 {
-		m.X += WIDTH
-	}
-	if m.X >= WIDTH {
-		m.X -= WIDTH
-	}
-	if m.Y < 0 {
-		m.Y += HEIGHT
+	m.PrevX, m.PrevY = m.X, m.Y
+	m.Vax = smooth(m.Vax, m.targetX, float32(config.Smoothing))
+	m.Vay = smooth(m.Vay, m.targetY, float32(config.Smoothing))
+	if config.Inertia {
+		m.accelerate()
 	}
-	if m.Y >= HEIGHT {
-		m.Y -= HEIGHT
+	dx, dy := m.Step()
+	x, y := m.X, m.Y
+	m.moveBy(dx, dy)
+	// measure the move itself, wrapping around the edge is not travel
+	m.Distance += math.Hypot(float64(m.X-x), float64(m.Y-y))
+	m.keepInBounds()
+	if config.Trail > 0 {
+		m.trail.add(m.X, m.Y)
 	}
 	m.last2Zero = m.lastZero
-	if m.Vax == 0.0 && m.Vay == 0.0 && m.Vhx == 0.0 && m.Vhy == 0.0 {
+	if m.Vax == 0.0 && m.Vay == 0.0 && m.Vhx == 0.0 && m.Vhy == 0.0 && m.velX == 0.0 && m.velY == 0.0 {
 		m.lastZero = true
 	} else {
 		m.lastZero = false
@@ -114,6 +227,69 @@ func (m *Marker) Update() {
 	}
 }
 
+// Record the state of button b
+func (m *Marker) SetButton(b int, pressed bool) {
+	if b < 0 || b >= 64 {
+		return
+	}
+	if pressed {
+		m.buttons |= 1 << uint(b)
+	} else {
+		m.buttons &^= 1 << uint(b)
+	}
+}
+
+// Is button b held down
+func (m *Marker) ButtonHeld(b int) bool {
+	if b < 0 || b >= 64 {
+		return false
+	}
+	return m.buttons&(1<<uint(b)) != 0
+}
+
+// Push the marker by dx, dy, wrapping around the screen edges
+func (m *Marker) Push(dx, dy int) {
+	m.X += dx
+	m.Y += dy
+	m.keepInBounds()
+}
+
+// Get the area the marker moves in, its Bounds or the whole playfield if they are not set
+func (m *Marker) Area() (x, y, w, h int) {
+	if m.Bounds.W == 0 || m.Bounds.H == 0 {
+		w, h = worldSize()
+		return 0, 0, w, h
+	}
+	return int(m.Bounds.X), int(m.Bounds.Y), int(m.Bounds.W), int(m.Bounds.H)
+}
+
+// Get a copy of the marker placed a fraction alpha (0 to 1) of the way from its previous position to its
+// current one.  A move that wrapped around the screen edge is not smoothed.
+func (m Marker) Interpolate(alpha float64) Marker {
+	_, _, w, h := m.Area()
+	m.X = lerpWrap(m.PrevX, m.X, alpha, w)
+	m.Y = lerpWrap(m.PrevY, m.Y, alpha, h)
+	return m
+}
+
+// smooth moves v towards target, keeping the fraction k of the old value (an exponential moving average)
+func smooth(v, target, k float32) float32 {
+	v = k*v + (1-k)*target
+	if d := v - target; d < 0.01 && d > -0.01 {
+		// close enough, and lets the marker come to a stop
+		v = target
+	}
+	return v
+}
+
+// lerpWrap interpolates between a and b, unless they are more than half of size apart
+func lerpWrap(a, b int, alpha float64, size int) int {
+	if d := b - a; d > size/2 || d < -size/2 {
+		return b
+	}
+	return a + int(math.Floor(float64(b-a)*alpha+0.5))
+}
+
 // Close the joystick associated with the marker
 func (m *Marker) Close() {
 	if m != nil {
@@ -121,25 +297,70 @@ func (m *Marker) Close() {
 			m.Joystick.Close()
 			m.Joystick = nil
 		}
+		m.cache.free()
 	}
 }
 
 // Get the bounding rectangle of the marker
 func (m Marker) Rect() *sdl.Rect {
-	var w, h int = RWIDTH, RHEIGHT
-	w += int(BIGMULTIPLIER * m.Big)
-	h += int(BIGMULTIPLIER * m.Big)
+	var w, h int = markerW, markerH
+	w += scaled(BIGMULTIPLIER * m.Big)
+	h += scaled(BIGMULTIPLIER * m.Big)
 	return &sdl.Rect{int16(m.X - (w / 2)), int16(m.Y - (h / 2)), uint16(w), uint16(h)}
 }
 
 // draw the marker
 func (m Marker) Draw(screen *sdl.Surface) {
-	screen.FillRect(m.Rect(), m.Color)
+	r := m.DrawRect()
+	if body := m.cache.get(&m, r); body != nil {
+		screen.Blit(r, body, nil)
+	} else if m.Sprite != nil {
+		m.drawSprite(screen, r)
+	} else {
+		screen.FillRect(r, m.Color)
+		if config.Patterns {
+			drawPattern(screen, r, m.Pattern)
+		}
+	}
+	if config.HighContrast {
+		Outline{R: *r, Color: CONTRAST_BORDER, Width: CONTRAST_BORDER_W}.Draw(screen)
+	}
+}
+
+// Get the rectangle the marker is drawn in.  With config.Squash set the marker stretches along the
+// direction it is moving in, the collision rectangle from Rect is not affected.
+func (m Marker) DrawRect() *sdl.Rect {
+	r := m.Rect()
+	if config.Squash <= 0 {
+		return r
+	}
+	sx, sy := stretchFactor(m.Vax+m.Vhx*HATMULTIPLIER, m.Vay+m.Vhy*HATMULTIPLIER, config.Squash)
+	w, h := int(float64(r.W)*sx), int(float64(r.H)*sy)
+	return &sdl.Rect{int16(m.X - (w / 2)), int16(m.Y - (h / 2)), uint16(w), uint16(h)}
+}
+
+// stretchFactor gives how much to scale the marker's width and height when moving at vx, vy.  It is
+// stretched by up to 1+magnitude along the main direction of movement (reached at full stick) and
+// squashed across it so the area stays the same.
+func stretchFactor(vx, vy float32, magnitude float64) (sx, sy float64) {
+	speed := math.Hypot(float64(vx), float64(vy)) / FULL_SPEED
+	if speed > 1 {
+		speed = 1
+	}
+	along := 1 + magnitude*speed
+	if math.Abs(float64(vx)) >= math.Abs(float64(vy)) {
+		return along, 1 / along
+	}
+	return 1 / along, along
 }
 
 // Does the marker intersect a given rectangle.
 func (m Marker) Intersects(r *sdl.Rect) bool {
-	s := m.Rect()
+	return intersects(m.Rect(), r)
+}
+
+// Do the rectangles s and r intersect
+func intersects(s, r *sdl.Rect) bool {
 	if int(s.X) > (int(r.X)+int(r.W)) || (int(s.X)+int(s.W)) < int(r.X) {
 		return false
 	}
@@ -149,9 +370,27 @@ func (m Marker) Intersects(r *sdl.Rect) bool {
 	return true
 }
 
-// Draw the given list of Drawables on the surface.  Items should be a list of Drawables
-func draw(screen *sdl.Surface, items *list.List) {
-	screen.FillRect(nil, uint32(0x00202020))
+// statusText builds the status line shown for player i
+func statusText(i int, m *Marker) string {
+	text := fmt.Sprintf("P%d", i+1)
+	if config.Mode == MODE_WHACK {
+		text += fmt.Sprintf("  score: %d", m.Score)
+	}
+	if config.ShowPresses {
+		text += fmt.Sprintf("  presses: %d  held: %d", m.Presses, m.Held)
+	}
+	return text
+}
+
+// Clear the surface to the background and draw the given list of Drawables on it.  Items should be a
+// list of Drawables
+func draw(screen *sdl.Surface, background *Background, items *list.List) {
+	background.Fill(screen)
+	drawItems(screen, items)
+}
+
+// Draw the given list of Drawables on top of what is already on the surface
+func drawItems(screen *sdl.Surface, items *list.List) {
 	for cur := items.Front(); cur != nil; cur = cur.Next() {
 		if d, ok := cur.Value.(Drawable); ok {
 			d.Draw(screen)
@@ -159,58 +398,337 @@ func draw(screen *sdl.Surface, items *list.List) {
 	}
 }
 
-// timeLoop generates a value on c at periodic intervals
+// timeLoop generates a value on c config.FrameRate times a second.  A ticker keeps the pace even, the
+// time taken to draw a frame does not add to the wait for the next one.
 func timeLoop(c chan bool) {
-	for {
-		time.Sleep( /*time.Millisecond*40*/ time.Second / 30)
+	ticker := time.NewTicker(time.Second / time.Duration(config.FrameRate))
+	for range ticker.C {
 		c <- true
 	}
 }
 
-//The main loop.  Handles drawing, events, ...  This should be broken up into a smaller set of functions
+// The main loop.  Handles drawing, events, ...  This should be broken up into a smaller set of functions
 // if more event logic is handled.
-func mainLoop(screen *sdl.Surface, markers []Marker, goals []*Goal) {
-	var curGoal int
-
+func mainLoop(window *Window, background *Background, font, bigFont *ttf.Font, game *Game, control *Control, sticks *Sticks, keyboard *Keyboard, recorder *Recorder, video *Video, replay *Replay, backend InputBackend, phone *Phone, midi *MIDI) {
 	timer := make(chan bool, 0)
 
 	running := true
 	redraw := true
 	requestRedraw := false
-	stickCount := len(markers)
+
+	var progress *Label
+	if len(config.Words) > 0 {
+		progress = &Label{Font: font, Color: config.TextColor, Y: 10}
+	}
+
+	var shapePrompt *Label
+	if config.GoalSet == GOALS_SHAPES && len(config.Words) == 0 && config.LayoutPath == "" {
+		shapePrompt = &Label{Font: font, Color: config.TextColor, Y: 10}
+		defer shapePrompt.Free()
+	}
+
+	winText := &Label{Font: bigFont, Color: sdl.Color{255, 255, 0, 0}}
+	winText.SetText("Well done!")
+	winText.X = (frameW - int(winText.Rect().W)) / 2
+	winText.Y = (frameH - int(winText.Rect().H)) / 2
+	defer winText.Free()
+	var results *Results // the end of race or tracking screen, once it is over
+	var finish *FinishScreen
+	defer func() {
+		if finish != nil {
+			finish.Free()
+		}
+	}()
+
+	idleText := &Label{Font: font, Color: config.TextColor}
+	idleText.SetText("Press a button to play")
+	idleText.X = (frameW - int(idleText.Rect().W)) / 2
+	idleText.Y = (frameH - int(idleText.Rect().H)) / 2
+	defer idleText.Free()
+
+	pauseMenu := NewPauseMenu(font, bigFont)
+	defer pauseMenu.Free()
+
+	testText := &Label{Font: font, Color: config.TextColor}
+	testText.SetText("test")
+	testText.X = frameW - int(testText.Rect().W) - 10
+	testText.Y = 10
+	defer testText.Free()
+
+	var dirty DirtyRects
+	screenshot := false // save the next frame drawn
+	showSticks := config.ShowSticks
+	debug := NewDebugOverlay(font)
+	debug.Shown = config.Debug
+	defer debug.Free()
+
+	var hud *HUD
+	if config.HUD && config.Mode != MODE_PAINT {
+		hud = NewHUD(font)
+		defer hud.Free()
+	}
+
+	var status []*Label
+	showStatus := config.ShowPresses || config.Mode == MODE_WHACK
+
+	teacher := newTeacherKeys()
+	var calibrator *Calibrator // the calibration screen, while it is up
+	var remapper *Remapper     // the button remapping screen, while it is up
+	tilt := NewTilt()
+	dragging := false // a goal is being dragged with the mouse in the editor
+
+	var canvas *Canvas
+	if config.Mode == MODE_PAINT {
+		canvas = NewCanvas(background)
+		defer canvas.Free()
+	}
+
+	var camera Camera
+	var world *sdl.Surface
+	if config.Camera != CAMERA_OFF {
+		w, h := worldSize()
+		world = sdl.CreateRGBSurface(sdl.SWSURFACE, w, h, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0)
+		defer world.Free()
+	}
 
 	// start the timer
 	go timeLoop(timer)
+
+	// the game is updated at its own rate, drawing smooths the markers between updates
+	updatePeriod := time.Second / time.Duration(config.UpdateRate)
+	lastUpdate := time.Now()
+	lastInput := lastUpdate
+	var inputs []Input // joystick input waiting for the next step
+	steps := 0         // steps taken, for recording and replaying
+	// someone is playing, from any of the inputs, so the game does not go idle or wakes up
+	active := func() {
+		lastInput = time.Now()
+		if game.Idle {
+			inputs = append(inputs, gameInput(GAME_WAKE, 0))
+			requestRedraw = true
+		}
+	}
+
+	// stays nil, and so never ready, when hot-plugging is turned off
+	var rescan <-chan time.Time
+	if config.Hotplug > 0 {
+		ticker := time.NewTicker(config.Hotplug)
+		defer ticker.Stop()
+		rescan = ticker.C
+	}
+
+	// stays nil, and so never ready, without phones
+	var phoneInputs chan Input
+	if phone != nil {
+		phoneInputs = phone.Inputs
+	}
+
+	// stays nil, and so never ready, without a MIDI controller
+	var midiInputs chan Input
+	if midi != nil {
+		midiInputs = midi.Inputs
+	}
+
+	// stays nil, and so never ready, without a control socket
+	var controlRequests chan ControlRequest
+	if control != nil {
+		controlRequests = control.Requests
+	}
 	for running {
 		if redraw {
+			now := time.Now()
+			if now.Sub(lastUpdate) > MAX_CATCHUP*updatePeriod {
+				// we were idle, do not try to make up the lost time
+				lastUpdate = now.Add(-updatePeriod)
+			}
+			for now.Sub(lastUpdate) >= updatePeriod {
+				if replay != nil && replay.Done(steps) {
+					// the game is left as it was at the end of the recording
+					break
+				}
+				lastUpdate = lastUpdate.Add(updatePeriod)
+				if replay != nil {
+					inputs = append(inputs[:0], replay.Inputs(steps)...)
+				}
+				if recorder != nil {
+					recorder.Record(steps, inputs)
+				}
+				Step(game, inputs, updatePeriod)
+				if canvas != nil {
+					canvas.Paint(game)
+				}
+				inputs = inputs[:0]
+				steps++
+			}
+			if replay != nil && replay.Done(steps) {
+				running = false
+			}
+			alpha := float64(now.Sub(lastUpdate)) / float64(updatePeriod)
+			screen := window.Screen()
+
 			items := list.New()
-			nextGoal := false
-			var curRect *sdl.Rect
-			if curGoal >= 0 && curGoal < len(goals) {
-				curRect = goals[curGoal].Rect()
-			}
-			for i := 0; i < stickCount; i++ {
-				markers[i].Update()
-				items.PushBack(markers[i])
-
-				if curRect != nil {
-					if markers[i].Intersects(curRect) {
-						nextGoal = true
+			if game.Editing {
+				for _, d := range game.EditorItems() {
+					items.PushBack(d)
+				}
+			} else {
+				if canvas != nil {
+					items.PushBack(canvas)
+				}
+				if game.Maze != nil {
+					items.PushBack(game.Maze)
+				}
+				for _, obj := range game.Objects {
+					items.PushBack(obj)
+				}
+				if config.Trail > 0 {
+					for i := range game.Markers {
+						items.PushBack(game.Markers[i].Trail())
+					}
+					for i := range game.Hands {
+						items.PushBack(game.Hands[i].Trail())
 					}
 				}
-			}
-			if nextGoal {
-				curGoal++
-				if curGoal >= len(goals) {
-					curGoal = 0
+				for i := range game.Markers {
+					m := game.Markers[i].Interpolate(alpha)
+					items.PushBack(m)
+					if hint, ok := game.Hint(&m); ok {
+						items.PushBack(hint)
+					}
+					if canvas != nil {
+						// show the color the marker paints in
+						items.PushBack(Outline{R: *m.Rect(), Color: paintColors[m.Paint], Width: 3})
+					}
+				}
+				for i := range game.Hands {
+					items.PushBack(game.Hands[i].Interpolate(alpha))
+				}
+				for _, d := range game.RaceOutlines() {
+					items.PushBack(d)
+				}
+				if flash := game.SimonFlash(); flash != nil {
+					items.PushBack(flash)
+				}
+				if dot := game.TrackDot(); dot != nil {
+					items.PushBack(dot)
+				}
+				for _, goal := range game.Visible() {
+					if flash := game.WrongFlash(goal); flash != nil {
+						items.PushBack(flash)
+					}
+					items.PushBack(game.Pulse(goal))
+				}
+				if game.Particles.Active() {
+					items.PushBack(game.Particles)
 				}
 			}
-			if curGoal >= 0 && curGoal < len(goals) {
-				items.PushBack(goals[curGoal])
+			if world != nil {
+				camera.Follow(game.Markers, config.Camera)
+				draw(world, background, items)
+				camera.Blit(screen, world)
 			}
 
-			draw(screen, items)
-			screen.Flip()
+			// the overlay is drawn in screen coordinates, on top of everything
+			overlay := list.New()
+			if game.Celebrating() {
+				overlay.PushBack(game.CelebrationFlash())
+			}
+			for showStatus && len(status) < len(game.Markers) {
+				status = append(status, &Label{Font: font, Color: config.TextColor, X: 10, Y: 10 + len(status)*24})
+			}
+			for i, l := range status {
+				l.SetText(statusText(i, &game.Markers[i]))
+				overlay.PushBack(l)
+			}
+			if hud != nil {
+				hud.Update(game)
+				overlay.PushBack(hud)
+			}
+			if game.Won && (config.Mode == MODE_RACE || config.Mode == MODE_TRACK) {
+				if results == nil {
+					results = NewResults(font, bigFont, game)
+					defer results.Free()
+				}
+				overlay.PushBack(results)
+			} else if game.Won {
+				overlay.PushBack(winText)
+			}
+			if game.Finished {
+				if finish == nil {
+					finish = NewFinishScreen(font, bigFont, game)
+				}
+				finish.Update(game)
+				overlay.PushBack(finish)
+			} else if finish != nil {
+				finish.Free()
+				finish = nil
+			}
+			if calibrator != nil {
+				overlay.PushBack(calibrator.Prompt)
+			}
+			if remapper != nil {
+				overlay.PushBack(remapper.Prompt)
+			} else if game.Paused {
+				overlay.PushBack(pauseMenu)
+			}
+			if game.Idle {
+				overlay.PushBack(idleText)
+			}
+			if game.TestMode {
+				overlay.PushBack(testText)
+			}
+			if progress != nil {
+				progress.SetText(game.WordProgress())
+				progress.X = (frameW - int(progress.Rect().W)) / 2
+				overlay.PushBack(progress)
+			}
+			if shapePrompt != nil {
+				shapePrompt.SetText(game.ShapePrompt())
+				shapePrompt.X = (frameW - int(shapePrompt.Rect().W)) / 2
+				overlay.PushBack(shapePrompt)
+			}
+			if world != nil && config.Minimap && config.World > 1 {
+				overlay.PushBack(Minimap{Game: game, Camera: camera})
+			}
+			if showSticks {
+				for _, v := range StickViews(game) {
+					overlay.PushBack(v)
+				}
+			}
+			if debug.Shown {
+				debug.Update()
+				overlay.PushBack(debug)
+			}
+			// only the parts of the screen that changed are drawn, when nothing else is done to the
+			// whole frame
+			var rects []sdl.Rect
+			if config.DirtyRects && world == nil && !window.Stretched() && !config.VSync && (config.Filter == FILTER_NORMAL || config.Filter == "") {
+				rects = dirty.Changed(screen, items, overlay)
+			} else {
+				dirty.Invalidate()
+			}
+			if rects != nil {
+				drawDirty(screen, background, rects, items, overlay)
+			} else {
+				if world == nil {
+					draw(screen, background, items)
+				}
+				drawItems(screen, overlay)
+				postProcess(screen, config.Filter)
+			}
+			if screenshot {
+				takeScreenshot(screen)
+				screenshot = false
+			}
+			if video != nil {
+				video.Frame(screen, time.Now())
+			}
+			if rects != nil {
+				window.FlipRects(rects)
+			} else {
+				window.Flip()
+			}
+			debug.Frame(time.Now())
 			//fmt.Printf(".")
 			redraw = false
 			requestRedraw = false
@@ -218,15 +736,72 @@ func mainLoop(screen *sdl.Surface, markers []Marker, goals []*Goal) {
 		select {
 		case <-timer:
 			zeroCnt := 0
-			for _, m := range markers {
+			for _, m := range game.Markers {
 				if m.last2Zero {
 					zeroCnt++
 				}
 			}
-			if zeroCnt < stickCount || requestRedraw {
+			for _, m := range game.Hands {
+				if m.last2Zero {
+					zeroCnt++
+				}
+			}
+			if sticks.Drift != nil {
+				learned := sticks.Drift.Learn(time.Now(), config.DriftTime, sticks.Names)
+				for _, key := range learned {
+					// the stick has not moved, so send its reading again with the drift taken off
+					if p, ok := sticks.MovePlayer(key[0]); ok {
+						if axis, value, ok := sticks.Axis(key[0], key[1], sticks.Drift.Last(key[0], key[1])); ok {
+							inputs = append(inputs, Input{Player: p, Kind: INPUT_AXIS, Index: axis, Value: value})
+						}
+					}
+				}
+				if len(learned) > 0 {
+					if err := sticks.Drift.save(config.DriftPath); err != nil {
+						fmt.Println(err)
+					}
+					requestRedraw = true
+				}
+			}
+			if !game.Idle && idleExpired(lastInput, time.Now(), config.IdleTimeout) {
+				inputs = append(inputs, gameInput(GAME_IDLE, 0))
+				requestRedraw = true
+			}
+			if zeroCnt < len(game.Markers)+len(game.Hands) || requestRedraw || game.Animating() || replay != nil {
 				redraw = true
+			} else {
+				debug.Skipped++
+			}
+		case <-rescan:
+			if sticks.Changed() {
+				sticks.Rescan()
+				game.ConnectSticks(sticks.Used(), minPlayers(keyboard))
+				sticks.applyProfiles(game)
+				requestRedraw = true
+			}
+		case in := <-phoneInputs:
+			active()
+			inputs = append(inputs, in)
+			requestRedraw = true
+		case in := <-midiInputs:
+			active()
+			inputs = append(inputs, in)
+			requestRedraw = true
+		case req := <-controlRequests:
+			// the command runs straight away, so the game has to be awake first.  Nothing is recorded
+			// with a control socket.
+			lastInput = time.Now()
+			game.Wake()
+			var reply string
+			reply, running = game.RunCommand(req.Name, req.Args)
+			req.Reply <- reply
+			requestRedraw = true
+
+		case _event := <-backend.Events():
+			debug.Event(_event)
+			if isInput(_event) {
+				active()
 			}
-		case _event := <-sdl.Events:
 			switch e := _event.(type) {
 			case sdl.QuitEvent:
 				running = false
@@ -235,76 +810,222 @@ func mainLoop(screen *sdl.Surface, markers []Marker, goals []*Goal) {
 				if e.Keysym.Sym == sdl.K_ESCAPE || e.Keysym.Sym == sdl.K_q {
 					running = false
 				}
-
-			case sdl.JoyAxisEvent:
-				if e.Axis < 2 {
-					val := float32(0.0)
-					if e.Value > 2000 || e.Value < -2000 {
-						val = float32(e.Value) / float32(uint32(0x0ffff))
+				if e.Keysym.Sym == sdl.K_RETURN && e.Keysym.Mod&(sdl.KMOD_LALT|sdl.KMOD_RALT) != 0 && e.State > 0 {
+					// alt+enter switches between fullscreen and a window
+					if err := window.ToggleFullscreen(); err != nil {
+						fmt.Println(err)
 					}
-					//fmt.Println("got joystick axis event ", e)
-
-					if e.Axis == 0 {
-						markers[e.Which].Vax = val
+					requestRedraw = true
+				} else if e.Keysym.Sym == sdl.K_PAUSE && e.State > 0 {
+					inputs = append(inputs, gameInput(GAME_PAUSE, 0))
+					requestRedraw = true
+				} else if game.Paused && e.State > 0 {
+					// the arrow keys and return work the pause menu
+					switch e.Keysym.Sym {
+					case sdl.K_UP:
+						pauseMenu.Move(-1)
+					case sdl.K_DOWN:
+						pauseMenu.Move(1)
+					case sdl.K_RETURN:
+						var in Input
+						in, running = pauseMenu.Choose()
+						inputs = append(inputs, in)
+					}
+					requestRedraw = true
+				}
+				if in, ok := keyboard.Key(e.Keysym.Sym, e.State > 0); ok {
+					inputs = append(inputs, in)
+					requestRedraw = true
+				}
+				if action := teacher.Action(e.Keysym.Sym); action != TEACHER_NONE && e.State > 0 {
+					inputs = append(inputs, gameInput(GAME_TEACHER, action))
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F8 && e.State > 0 {
+					inputs = append(inputs, gameInput(GAME_TEST_MODE, 0))
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F4 && e.State > 0 {
+					// F4 starts calibrating the sticks, or gives up on it
+					if calibrator == nil {
+						calibrator = NewCalibrator(font)
+					} else {
+						calibrator.Free()
+						calibrator = nil
+					}
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F9 && e.State > 0 {
+					// F9 starts remapping a stick's buttons, or gives up on it
+					if remapper == nil {
+						remapper = NewRemapper(font)
 					} else {
-						markers[e.Which].Vay = val
+						remapper.Free()
+						remapper = nil
 					}
 					requestRedraw = true
 				}
+				if e.Keysym.Sym == sdl.K_F10 && e.State > 0 {
+					// F10 turns up and down around on every stick
+					config.FlipY = !config.FlipY
+					fmt.Println("up and down flipped:", config.FlipY)
+				}
+				if e.Keysym.Sym == sdl.K_PRINT && e.State > 0 {
+					// print screen saves what is on the screen
+					screenshot = true
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F11 && e.State > 0 {
+					// F11 shows and hides what each controller is doing
+					showSticks = !showSticks
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F12 && e.State > 0 {
+					// F12 shows and hides the debug overlay
+					debug.Shown = !debug.Shown
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F1 && e.State > 0 {
+					// F1 switches the high contrast theme, F6 is the teacher's restart
+					config.HighContrast = !config.HighContrast
+					dirty.Invalidate()
+					requestRedraw = true
+				}
+				if e.Keysym.Sym == sdl.K_F3 && e.State > 0 {
+					if recorder != nil || replay != nil {
+						// moving goals by hand is not part of the recording
+						fmt.Println("the editor cannot be used while recording or replaying")
+					} else {
+						game.ToggleEditor()
+						requestRedraw = true
+					}
+				}
+				if e.Keysym.Sym == sdl.K_F2 && e.State > 0 {
+					if err := saveLayout(config.LayoutOut, game.Goals); err != nil {
+						fmt.Println(err)
+					} else {
+						fmt.Println("saved the goal layout to", config.LayoutOut)
+					}
+				}
 
-			case sdl.JoyButtonEvent:
-				if e.State > 0 {
-					markers[e.Which].Big++
-				} else {
-					markers[e.Which].Big--
+			case sdl.MouseButtonEvent:
+				if game.Editing && e.Button == sdl.BUTTON_LEFT {
+					dragging = e.State > 0 && game.SelectAt(camera.ScreenToWorld(window.ToFrame(int(e.X), int(e.Y))))
+					requestRedraw = true
+				} else if !game.Editing && config.MousePlayer >= 0 && e.Button == sdl.BUTTON_LEFT {
+					inputs = append(inputs, Input{Player: config.MousePlayer, Kind: INPUT_BUTTON, Index: 0, Value: int16(e.State)})
+					requestRedraw = true
 				}
-				if markers[e.Which].Big < 0 {
-					markers[e.Which].Big = 0
+				if !game.Editing && config.TouchPlayer >= 0 && e.Button == sdl.BUTTON_LEFT && e.State > 0 {
+					// a touch puts the marker under the finger
+					x, y := camera.ScreenToWorld(window.ToFrame(int(e.X), int(e.Y)))
+					inputs = append(inputs, positionInputs(config.TouchPlayer, x, y)...)
+					requestRedraw = true
 				}
-				requestRedraw = true
 
-			case sdl.JoyHatEvent:
+			case sdl.MouseMotionEvent:
+				if game.Editing && dragging {
+					x, y := camera.ScreenToWorld(window.ToFrame(int(e.X), int(e.Y)))
+					moveGoal(game.Goals[game.Selected], x, y)
+					requestRedraw = true
+				} else if !game.Editing && config.MousePlayer >= 0 {
+					// the marker goes where the pointer is
+					x, y := camera.ScreenToWorld(window.ToFrame(int(e.X), int(e.Y)))
+					inputs = append(inputs, positionInputs(config.MousePlayer, x, y)...)
+					requestRedraw = true
+				}
+				if !game.Editing && config.TouchPlayer >= 0 && e.State&(1<<(sdl.BUTTON_LEFT-1)) != 0 {
+					// and follows it while it is dragged
+					x, y := camera.ScreenToWorld(window.ToFrame(int(e.X), int(e.Y)))
+					inputs = append(inputs, positionInputs(config.TouchPlayer, x, y)...)
+					requestRedraw = true
+				}
 
-				switch e.Value {
-				case sdl.HAT_CENTERED:
-					markers[e.Which].Vhx = 0.0
-					markers[e.Which].Vhy = 0.0
-				case sdl.HAT_UP:
-					markers[e.Which].Vhx = 0.0
-					markers[e.Which].Vhy = -1.0
-				case sdl.HAT_RIGHT:
-					markers[e.Which].Vhx = 1.0
-					markers[e.Which].Vhy = 0.0
-				case sdl.HAT_DOWN:
-					markers[e.Which].Vhx = 0.0
-					markers[e.Which].Vhy = 1.0
-				case sdl.HAT_LEFT:
-					markers[e.Which].Vhx = -1.0
-					markers[e.Which].Vhy = 0.0
-				case sdl.HAT_RIGHTUP:
-					markers[e.Which].Vhx = 1.0
-					markers[e.Which].Vhy = -1.0
-				case sdl.HAT_RIGHTDOWN:
-					markers[e.Which].Vhx = 1.0
-					markers[e.Which].Vhy = 1.0
-				case sdl.HAT_LEFTUP:
-					markers[e.Which].Vhx = -1.0
-					markers[e.Which].Vhy = -1.0
-				case sdl.HAT_LEFTDOWN:
-					markers[e.Which].Vhx = -1.0
-					markers[e.Which].Vhy = 1.0
-				}
-				//fmt.Println("Hat event ", e, " (",markers[e.Which].Vhx,",",markers[e.Which].Vhy,")")
-				requestRedraw = true
-			case sdl.ResizeEvent:
-				//println("resize screen ", e.W, e.H)
-				panic("Resize not supported yet")
+			case sdl.JoyAxisEvent:
+				debug.Axis(int(e.Which), int(e.Axis), e.Value)
+				if calibrator != nil {
+					calibrator.Axis(int(e.Which), int(e.Axis), e.Value)
+				} else if p, ok := sticks.MovePlayer(int(e.Which)); ok {
+					if sticks.Drift != nil {
+						sticks.Drift.Axis(int(e.Which), int(e.Axis), e.Value, time.Now())
+					}
+					if int(e.Which) == config.TiltDevice {
+						e.Value = tilt.Apply(int(e.Axis), e.Value)
+					}
+					if in, ok := sticks.Input(e); ok && game.Paused {
+						requestRedraw = pauseMenu.Axis(p, in.Index, in.Value) || requestRedraw
+					} else if ok {
+						inputs = append(inputs, in)
+						requestRedraw = true
+					}
+				}
 
-				//screen = sdl.SetVideoMode(int(e.W), int(e.H), 32, sdl.RESIZABLE)
+			case sdl.JoyButtonEvent:
+				if sticks.Drift != nil {
+					sticks.Drift.Button(time.Now())
+				}
+				if config.TiltDevice >= 0 && int(e.Which) == config.TiltDevice && int(e.Button) == config.TiltRecenter && e.State > 0 {
+					tilt.Recenter()
+				}
+				if calibrator != nil {
+					if e.State > 0 && calibrator.Button() {
+						calibrator.Merge(sticks.Cal, sticks.Names)
+						if err := sticks.Cal.save(config.CalibrationPath); err != nil {
+							fmt.Println(err)
+						}
+						calibrator.Free()
+						calibrator = nil
+					}
+					requestRedraw = true
+				} else if remapper != nil {
+					if e.State > 0 && remapper.Button(int(e.Which), int(e.Button)) {
+						remapper.Save(sticks.Actions, sticks.Names)
+						if err := sticks.Actions.save(config.ButtonsPath); err != nil {
+							fmt.Println(err)
+						}
+						remapper.Free()
+						remapper = nil
+					}
+					requestRedraw = true
+				} else if _, ok := sticks.ButtonPlayer(int(e.Which)); ok {
+					if sticks.IsButton(int(e.Which), int(e.Button), BUTTON_GUIDE) {
+						// the guide button switches between fullscreen and a window
+						if e.State > 0 {
+							if err := window.ToggleFullscreen(); err != nil {
+								fmt.Println(err)
+							}
+						}
+					} else if b, ok := sticks.MapButton(int(e.Which), int(e.Button)); ok && b == config.ScreenshotButton {
+						if e.State > 0 {
+							screenshot = true
+						}
+					} else if game.Paused && !sticks.Pauses(int(e.Which), int(e.Button)) {
+						// any other button picks from the pause menu
+						if e.State > 0 {
+							var in Input
+							in, running = pauseMenu.Choose()
+							inputs = append(inputs, in)
+						}
+					} else if in, ok := sticks.Input(e); ok {
+						inputs = append(inputs, in)
+					}
+					requestRedraw = true
+				}
 
-				//if screen == nil {
-				//	fmt.Println(sdl.GetError())
-				//}
+			case sdl.JoyHatEvent:
+				if in, ok := sticks.Input(e); ok && game.Paused {
+					requestRedraw = pauseMenu.Hat(uint8(in.Value)) || requestRedraw
+				} else if ok {
+					inputs = append(inputs, in)
+					//fmt.Println("Hat event ", e)
+					requestRedraw = true
+				}
+			case sdl.ResizeEvent:
+				if err := window.Resize(int(e.W), int(e.H)); err != nil {
+					fmt.Println(err)
+					running = false
+				}
+				requestRedraw = true
 			}
 		}
 		// yeild to allow other activities (such as the timer loop)
@@ -316,65 +1037,144 @@ func main() {
 	//runtime.GOMAXPROCS(runtime.NumCPU()*2)
 
 	var err error
+	parseFlags()
+	var replay *Replay
+	if config.ReplayPath != "" {
+		var err error
+		if replay, err = loadReplay(config.ReplayPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		// the replay only comes out the same with the seed and rate it was recorded with
+		config.Seed, config.UpdateRate = replay.Seed, replay.Rate
+	}
 	os.Setenv("SDL_VIDEODRIVER", "x11")
 
-	rand.Seed(time.Now().Unix())
-
-	GOALS := []rune(GOALS_SRC)
+	rand.Seed(config.Seed)
 
 	runtime.GOMAXPROCS(1)
 	//f, _ := os.Create("prof.dat")
 	//pprof.StartCPUProfile(f)
 	//defer pprof.StopCPUProfile()
 
+	if err = chooseDisplay(config.Display, config.WindowPos); err != nil {
+		fmt.Println(err)
+		return
+	}
 	if sdl.Init(sdl.INIT_EVERYTHING) != 0 {
 		fmt.Println(sdl.GetError())
 		return
 	}
 	defer sdl.Quit()
 
+	// the window comes first, the fonts and goals are sized for the frame it gives
+	window, err := OpenWindow(config.Fullscreen, config.VSync)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer window.Free()
+
 	// load the font system and a font
 	if err = ttf.Init(); err != nil {
 		fmt.Println(err)
 		return
 	}
 	defer ttf.Quit()
-	var fnt *ttf.Font
-	if fnt, err = ttf.OpenFont("font.ttf", 60); err != nil {
-		fmt.Println(sdl.GetError())
+	var assets *Assets
+	if assets, err = LoadAssets(config.AssetDir); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer assets.Free()
+	if config.Font != "" {
+		assets.Set("font", config.Font)
+	}
+	var fnt, smallFnt *ttf.Font
+	if fnt, err = assets.Font("font", scaled(config.GoalSize)); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if smallFnt, err = assets.Font("font", scaled(20)); err != nil {
+		fmt.Println(err)
 		return
 	}
-	defer fnt.Close()
 
 	// build the goals
-	goals := make([]*Goal, len(GOALS))
-	for i, ch := range GOALS {
-		goals[i] = NewGoal(fnt, ch, i)
-		goals[i].X = goals[i].W/2 + rand.Intn(WIDTH-goals[i].W)
-		goals[i].Y = goals[i].H/2 + rand.Intn(HEIGHT-goals[i].H)
-		goals[i].Hidden = false
+	makeGoals := func(src string, rng *rand.Rand) []*Goal {
+		return buildGoals(fnt, src, rng)
 	}
-
-	stickCount := sdl.NumJoysticks()
-	if stickCount == 0 {
-		panic("No joysticks available")
+	var goals []*Goal
+	var maze *Maze
+	if config.Mode == MODE_MAZE {
+		if maze, err = loadMaze(config.MazePath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		goals = maze.Goals(fnt)
+	} else if config.LayoutPath != "" {
+		var layout *Layout
+		if layout, err = loadLayout(config.LayoutPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		goals = layoutGoals(fnt, layout)
+	} else if len(config.Words) > 0 {
+		goals = makeGoals(config.Words[0], rand.New(rand.NewSource(config.Seed)))
+	} else {
+		goals = buildGoalSet(fnt, config.GoalSet, rand.New(rand.NewSource(config.Seed)))
 	}
-	markers := make([]Marker, stickCount)
-	fmt.Println("Found ", stickCount, " joysticks:")
-
-	colors := [3]uint32{uint32(0x00aa0000), uint32(0x00009900), uint32(0x00000099)}
 
-	for i := 0; i < stickCount; i++ {
-		fmt.Println(i+1, " ", sdl.JoystickName(i))
-		markers[i] = Marker{Joystick: sdl.JoystickOpen(i), X: WIDTH / 2, Y: HEIGHT / 2, Color: colors[i%len(colors)]}
-		defer markers[i].Close()
+	sticks := OpenSticks()
+	defer sticks.Close()
+	if sticks.Cal, err = loadCalibrations(config.CalibrationPath); err != nil {
+		fmt.Println(err)
+		return
 	}
-
-	var screen = sdl.SetVideoMode(WIDTH, HEIGHT, 32, 0 /*sdl.RESIZABLE*/)
-
-	if screen == nil {
-		fmt.Println(sdl.GetError())
+	if sticks.Actions, err = loadButtonActions(config.ButtonsPath); err != nil {
+		fmt.Println(err)
+		return
 	}
+	if config.DriftTime > 0 {
+		if sticks.Drift, err = loadDrift(config.DriftPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if config.ProfilesPath != "" {
+		if sticks.Profiles, err = loadProfiles(config.ProfilesPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		// a profile's buttons beat the ones saved by the remap screen
+		for name, p := range sticks.Profiles {
+			if p.Buttons != nil {
+				sticks.Actions[name] = p.Buttons
+			}
+		}
+	}
+	if config.ControllerDB != "" {
+		if sticks.Maps, err = loadControllerDB(config.ControllerDB); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	keyboard := &Keyboard{Player: config.KeyboardPlayer}
+	var evdev *EvdevInput
+	if config.Evdev != "" {
+		if evdev, err = OpenEvdev(strings.Split(config.Evdev, ","), sdl.Events); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if len(sticks.Open) == 0 && evdev == nil && keyboard.Player < 0 {
+		fmt.Println("No joysticks available, using the keyboard for player 1")
+		keyboard.Player = 0
+	}
+	if config.SelfTest {
+		selfTest(os.Stdout, sticks.Open)
+	}
+	screen := window.Video
 
 	var video_info = sdl.GetVideoInfo()
 
@@ -390,5 +1190,130 @@ func main() {
 		fmt.Println("GetKeyName broken")
 		return
 	}
-	mainLoop(screen, markers, goals)
+	if config.ChooseSticks && len(sticks.Open) > 1 && !chooseSticks(screen, smallFnt, sticks) {
+		return
+	}
+	audio := OpenAudio(assets)
+	defer audio.Close()
+
+	sprites := NewSprites(assets)
+	defer sprites.Free()
+	background, err := LoadBackground(assets)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer background.Free()
+
+	game := NewGame(nil, goals)
+	game.Maze = maze
+	game.Sprite = sprites.Get
+	if maze == nil {
+		game.AddObstacles(config.Obstacles)
+	}
+	players := minPlayers(keyboard)
+	if replay != nil && replay.Players > players {
+		players = replay.Players
+	}
+	if evdev != nil && config.Inputs.Players(len(evdev.Paths)) > players {
+		players = config.Inputs.Players(len(evdev.Paths))
+	}
+	game.ConnectSticks(sticks.Used(), players)
+	sticks.applyProfiles(game)
+	game.MakeGoals = makeGoals
+	game.OnGoals = audio.Prepare
+	audio.Prepare(game.Goals, len(game.Goals))
+	if config.Mode == MODE_RACE {
+		game.StartRace(func(rng *rand.Rand) []*Goal {
+			if len(config.Words) > 0 {
+				return makeGoals(config.Words[0], rng)
+			}
+			return buildGoalSet(fnt, config.GoalSet, rng)
+		})
+	}
+	game.OnCollect = func(goal *Goal, count int) {
+		audio.Collect(goal.Order, count)
+	}
+	if evdev != nil {
+		game.OnRumble = func(player int) {
+			for dev := range evdev.Paths {
+				if p, ok := config.Inputs.MovePlayer(dev); ok && p == player {
+					evdev.Rumble(dev)
+				}
+			}
+		}
+	}
+	var control *Control
+	if config.ControlPath != "" {
+		if control, err = ListenControl(config.ControlPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer control.Close()
+		game.OnEvent = control.Broadcast
+	}
+	var recorder *Recorder
+	if config.RecordPath != "" {
+		if recorder, err = NewRecorder(config.RecordPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer recorder.Close()
+	}
+	var video *Video
+	if config.VideoPath != "" {
+		if video, err = NewVideo(config.VideoPath, config.VideoRate, config.VideoShrink); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer func() {
+			if err := video.Close(); err != nil {
+				fmt.Println("cannot save video:", err)
+			}
+		}()
+	}
+	var backend InputBackend = sdlInput{}
+	if evdev != nil {
+		backend = evdev
+	}
+	if config.FakeInput != "" {
+		var script *os.File
+		if script, err = os.Open(config.FakeInput); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer script.Close()
+		fake := NewFakeInput()
+		go fake.Forward(sdl.Events)
+		go func() {
+			if err := fake.Run(script); err != nil {
+				fmt.Println(config.FakeInput, err)
+			}
+		}()
+		backend = fake
+	}
+	var phone *Phone
+	if config.PhoneAddr != "" {
+		if phone, err = ListenPhone(config.PhoneAddr); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer phone.Close()
+	}
+	var midi *MIDI
+	if config.MidiPath != "" {
+		if midi, err = OpenMIDI(config.MidiPath); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer midi.Close()
+	}
+	mainLoop(window, background, smallFnt, fnt, game, control, sticks, keyboard, recorder, video, replay, backend, phone, midi)
+
+	printSummary(os.Stdout, game)
+	if config.CSVPath != "" {
+		if err = writeStatsCSV(config.CSVPath, game.Markers); err != nil {
+			fmt.Println(err)
+		}
+	}
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+)
+
+// adapt moves the difficulty level after a goal took elapsed to collect.  Quick collections make the
+// game harder and slow ones make it easier, a step at a time.
+func (g *Game) adapt(elapsed float64) {
+	switch {
+	case elapsed < config.AdaptFast.Seconds():
+		g.Level += config.AdaptStep
+	case elapsed > config.AdaptSlow.Seconds():
+		g.Level -= config.AdaptStep
+	}
+	g.Level = math.Max(0, math.Min(1, g.Level))
+}
+
+// adaptGoal makes the goal about to be shown as hard as the level says: smaller, down to
+// config.AdaptMinScale of its size, and further from the markers, up to config.AdaptDistance pixels.
+func (g *Game) adaptGoal(goal *Goal) {
+	if goal.full == nil {
+		goal.full = goal.Surface
+	} else if goal.Surface != goal.full {
+		goal.Surface.Free()
+	}
+	scale := 1 - g.Level*(1-config.AdaptMinScale)
+	w, h := int(float64(goal.full.W)*scale), int(float64(goal.full.H)*scale)
+	if s := scaleSurface(goal.full, w, h); s != nil && scale < 1 {
+		goal.Surface = s
+	} else {
+		if s != nil {
+			s.Free()
+		}
+		goal.Surface = goal.full
+	}
+	goal.W, goal.H = int(goal.Surface.W), int(goal.Surface.H)
+
+	// goals in a maze or a layout stay where they were put
+	if config.Mode != MODE_ALPHABET || g.Maze != nil || config.LayoutPath != "" {
+		return
+	}
+	minDist := g.Level * config.AdaptDistance
+	place(goal, nil, func() (int, int) {
+		w, h := worldSize()
+		for i := 0; i < PLACE_TRIES; i++ {
+			x, y := goal.W/2+g.rng.Intn(w-goal.W), goal.H/2+g.rng.Intn(h-goal.H)
+			if g.markersFarFrom(x, y, minDist) {
+				return x, y
+			}
+		}
+		return goal.X, goal.Y
+	})
+}
+
+// markersFarFrom reports whether all the markers are at least minDist from x, y
+func (g *Game) markersFarFrom(x, y int, minDist float64) bool {
+	for i := range g.Markers {
+		if math.Hypot(float64(g.Markers[i].X-x), float64(g.Markers[i].Y-y)) < minDist {
+			return false
+		}
+	}
+	return true
+}
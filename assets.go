@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"os"
+	"path/filepath"
+)
+
+// the manifest file in the asset directory
+const ASSET_MANIFEST = "assets.json"
+
+// files used for assets the manifest does not mention
+var defaultAssets = map[string]string{
+	"font": "font.ttf",
+}
+
+type fontKey struct {
+	name string
+	size int
+}
+
+// Assets loads the images and fonts the game uses by logical name.  An asset directory may hold a
+// manifest (assets.json), a JSON object mapping names to files in the directory.  Assets are loaded
+// when first asked for and kept until Free is called.
+type Assets struct {
+	Dir    string
+	files  map[string]string
+	images map[string]*sdl.Surface
+	fonts  map[fontKey]*ttf.Font
+	warned map[string]bool
+	temp   []string // temporary files to remove in Free
+}
+
+// LoadAssets reads the manifest in dir.  A directory without a manifest only provides the defaults.
+func LoadAssets(dir string) (*Assets, error) {
+	a := &Assets{
+		Dir:    dir,
+		files:  make(map[string]string),
+		images: make(map[string]*sdl.Surface),
+		fonts:  make(map[fontKey]*ttf.Font),
+		warned: make(map[string]bool),
+	}
+	for name, file := range defaultAssets {
+		a.files[name] = file
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ASSET_MANIFEST))
+	if os.IsNotExist(err) {
+		return a, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = parseManifest(data, a.files); err != nil {
+		return nil, fmt.Errorf("%s: %v", filepath.Join(dir, ASSET_MANIFEST), err)
+	}
+	return a, nil
+}
+
+// parseManifest adds the name to file mappings in the manifest data to files
+func parseManifest(data []byte, files map[string]string) error {
+	var manifest map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+	for name, file := range manifest {
+		files[name] = file
+	}
+	return nil
+}
+
+// Path gives the file for the named asset, or "" if there is none
+func (a *Assets) Path(name string) string {
+	file, ok := a.files[name]
+	if !ok || file == "" {
+		return ""
+	}
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(a.Dir, file)
+}
+
+// Set makes the named asset come from file, instead of what the manifest says
+func (a *Assets) Set(name, file string) {
+	a.files[name] = file
+}
+
+// Image returns the named image, or nil (after a warning) if it cannot be loaded
+func (a *Assets) Image(name string) *sdl.Surface {
+	if img, ok := a.images[name]; ok {
+		return img
+	}
+	var img *sdl.Surface
+	if path := a.Path(name); path == "" {
+		a.warn(name, "no file for image "+name)
+	} else if img = sdl.Load(path); img == nil {
+		a.warn(name, "cannot load image "+path+": "+sdl.GetError())
+	}
+	// remember failures too, so they are only tried once
+	a.images[name] = img
+	return img
+}
+
+// Font returns the named font at the given size.  If the main "font" is missing another is found
+// with fallbackFont.
+func (a *Assets) Font(name string, size int) (*ttf.Font, error) {
+	key := fontKey{name, size}
+	if f, ok := a.fonts[key]; ok {
+		return f, nil
+	}
+	path := a.Path(name)
+	if _, err := os.Stat(path); name == "font" && (path == "" || err != nil) {
+		fallback, temp, err := fallbackFont()
+		if err != nil {
+			return nil, err
+		}
+		a.warn(name, "no font at "+path+", using "+fallback)
+		if temp {
+			a.temp = append(a.temp, fallback)
+		}
+		// the other sizes use it too
+		a.files[name] = fallback
+		path = fallback
+	}
+	if path == "" {
+		return nil, fmt.Errorf("no file for font %s", name)
+	}
+	f, err := ttf.OpenFont(path, size)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load font %s: %v", path, err)
+	}
+	a.fonts[key] = f
+	return f, nil
+}
+
+// warn prints a warning about the named asset, once
+func (a *Assets) warn(name, msg string) {
+	if !a.warned[name] {
+		a.warned[name] = true
+		fmt.Println("warning:", msg)
+	}
+}
+
+// Free releases everything that has been loaded
+func (a *Assets) Free() {
+	for name, img := range a.images {
+		if img != nil {
+			img.Free()
+		}
+		delete(a.images, name)
+	}
+	for key, f := range a.fonts {
+		f.Close()
+		delete(a.fonts, key)
+	}
+	for _, path := range a.temp {
+		os.Remove(path)
+	}
+	a.temp = nil
+}
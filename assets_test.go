@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"empty", `{}`, map[string]string{"font": "font.ttf"}, false},
+		{"adds", `{"background": "sky.png"}`, map[string]string{"font": "font.ttf", "background": "sky.png"}, false},
+		{"overrides the default", `{"font": "big.ttf"}`, map[string]string{"font": "big.ttf"}, false},
+		{"not an object", `["sky.png"]`, nil, true},
+		{"not json", `background = sky.png`, nil, true},
+	}
+	for _, tt := range tests {
+		files := map[string]string{"font": "font.ttf"}
+		err := parseManifest([]byte(tt.data), files)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v", tt.name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if len(files) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, files, tt.want)
+		}
+		for name, file := range tt.want {
+			if files[name] != file {
+				t.Errorf("%s: %s is %q, want %q", tt.name, name, files[name], file)
+			}
+		}
+	}
+}
+
+func TestAssetPaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ASSET_MANIFEST), []byte(`{"sky": "pics/sky.png", "sun": "/usr/share/sun.png", "none": ""}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := LoadAssets(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ name, want string }{
+		{"sky", filepath.Join(dir, "pics/sky.png")},
+		{"sun", "/usr/share/sun.png"},
+		{"font", filepath.Join(dir, "font.ttf")},
+		{"none", ""},
+		{"missing", ""},
+	}
+	for _, tt := range tests {
+		if got := a.Path(tt.name); got != tt.want {
+			t.Errorf("Path(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssetImageCache(t *testing.T) {
+	a, err := LoadAssets(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a hit gives the image already loaded
+	img := &sdl.Surface{}
+	a.images["sky"] = img
+	if a.Image("sky") != img {
+		t.Errorf("cached image not used")
+	}
+	// a miss is remembered, so it is only tried and warned about once
+	if a.Image("sun") != nil {
+		t.Errorf("image with no file loaded")
+	}
+	if _, ok := a.images["sun"]; !ok || !a.warned["sun"] {
+		t.Errorf("missing image not remembered")
+	}
+	a.Set("sun", "sun.png")
+	if a.Image("sun") != nil {
+		t.Errorf("missing image tried again")
+	}
+}
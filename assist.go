@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+)
+
+// Are the assists (the tractor, hints, ...) available.  In test mode they are all off so unaided
+// performance can be measured.
+func (g *Game) AssistsEnabled() bool {
+	return !g.TestMode
+}
+
+// Switch between practice mode, with all the configured assists, and test mode, with none
+func (g *Game) ToggleTestMode() {
+	g.TestMode = !g.TestMode
+}
+
+// applyTractor pulls each marker holding the tractor button towards the current goal, provided it is
+// within range
+func (g *Game) applyTractor() {
+	goal := g.Current()
+	if goal == nil || config.TractorButton < 0 || !g.AssistsEnabled() {
+		return
+	}
+	for i := range g.Markers {
+		m := &g.Markers[i]
+		m.Push(tractorPull(m, goal.X, goal.Y))
+	}
+}
+
+// tractorPull gives the move towards the goal at gx, gy for the marker this frame.  There is only a
+// pull while the marker is holding config.TractorButton and is within config.TractorRange of the goal.
+// The marker never overshoots the goal.
+func tractorPull(m *Marker, gx, gy int) (dx, dy int) {
+	if config.TractorButton < 0 || !m.ButtonHeld(config.TractorButton) {
+		return 0, 0
+	}
+	vx, vy := float64(gx-m.X), float64(gy-m.Y)
+	d := math.Hypot(vx, vy)
+	if d == 0 || d > config.TractorRange {
+		return 0, 0
+	}
+	step := math.Min(config.TractorStrength, d)
+	return int(vx / d * step), int(vy / d * step)
+}
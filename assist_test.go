@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTractorPull(t *testing.T) {
+	useConfig(t, Config{TractorButton: 2, TractorRange: 200, TractorStrength: 6})
+	tests := []struct {
+		name         string
+		held         bool
+		x, y         int // the marker, the goal is at 500,500
+		wantX, wantY int
+	}{
+		{"held in range", true, 400, 500, 6, 0},
+		{"not held", false, 400, 500, 0, 0},
+		{"held out of range", true, 200, 500, 0, 0},
+		{"held diagonally", true, 400, 400, 4, 4},
+		{"no overshoot", true, 497, 500, 3, 0},
+		{"on the goal", true, 500, 500, 0, 0},
+	}
+	for _, tt := range tests {
+		m := Marker{X: tt.x, Y: tt.y}
+		m.SetButton(2, tt.held)
+		if dx, dy := tractorPull(&m, 500, 500); dx != tt.wantX || dy != tt.wantY {
+			t.Errorf("%s: pulled %d,%d, want %d,%d", tt.name, dx, dy, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestTractorOffInTestMode(t *testing.T) {
+	useConfig(t, Config{TractorButton: 2, TractorRange: 200, TractorStrength: 6})
+	for _, testMode := range []bool{false, true} {
+		g := NewGame([]Marker{{X: 400, Y: 500}}, []*Goal{{X: 500, Y: 500, W: 40, H: 40}})
+		g.TestMode = testMode
+		g.Markers[0].SetButton(2, true)
+		g.applyTractor()
+		if moved := g.Markers[0].X != 400; moved == testMode {
+			t.Errorf("test mode %v: marker pulled %v", testMode, moved)
+		}
+	}
+}
+
+func TestAssistsOffInTestMode(t *testing.T) {
+	useConfig(t, Config{Hint: true, HintDistance: 100, Pulse: true, CollectButton: -1})
+	for _, testMode := range []bool{false, true} {
+		g := NewGame([]Marker{{X: 100, Y: 100}}, []*Goal{{X: 800, Y: 600, W: 40, H: 40}})
+		g.TestMode = testMode
+		if _, ok := g.Hint(&g.Markers[0]); ok == testMode {
+			t.Errorf("test mode %v: hint shown %v", testMode, ok)
+		}
+		if pulsing := g.Pulsing(); pulsing == testMode {
+			t.Errorf("test mode %v: goal pulsing %v", testMode, pulsing)
+		}
+	}
+}
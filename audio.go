@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/mixer"
+	"math"
+	"os"
+)
+
+// Audio plays the game's sounds through SDL_mixer.  A nil *Audio is silent.
+type Audio struct {
+	collectPath string                  // the collection sound, from the "collect" asset
+	collect     *mixer.Chunk            // the collection sound at its own pitch
+	pitched     map[[2]int]*mixer.Chunk // the collection sound pitched for a goal, by order and goal count
+}
+
+// OpenAudio starts SDL_mixer and loads the sounds.  It returns nil, and the game is silent, if there
+// is no audio device or no collection sound.
+func OpenAudio(assets *Assets) *Audio {
+	path := assets.Path("collect")
+	if path == "" {
+		return nil
+	}
+	if mixer.OpenAudio(22050, mixer.DEFAULT_FORMAT, 2, 1024) != 0 {
+		fmt.Println("no audio:", mixer.GetError())
+		return nil
+	}
+	a := &Audio{collectPath: path, pitched: make(map[[2]int]*mixer.Chunk)}
+	if a.collect = mixer.LoadWAV(path); a.collect == nil {
+		fmt.Println("cannot load", path, ":", mixer.GetError())
+		mixer.CloseAudio()
+		return nil
+	}
+	return a
+}
+
+// Collect plays the collection sound for the goal with the given order out of count goals.  With
+// config.PitchByOrder the sound rises an octave over the sequence.
+func (a *Audio) Collect(order, count int) {
+	if a == nil {
+		return
+	}
+	chunk := a.collect
+	if c, ok := a.pitched[[2]int{order, count}]; ok && config.PitchByOrder {
+		chunk = c
+	}
+	chunk.PlayChannel(-1, 0)
+}
+
+// Prepare makes the pitched collection sounds for goals out of count goals, so nothing has to be
+// loaded when a goal is collected.  SDL_mixer cannot change the pitch of a sample, so the sound is
+// resampled and loaded again.  Goals without a pitched sound get the plain one.
+func (a *Audio) Prepare(goals []*Goal, count int) {
+	if a == nil || !config.PitchByOrder {
+		return
+	}
+	for _, goal := range goals {
+		key := [2]int{goal.Order, count}
+		if _, ok := a.pitched[key]; ok {
+			continue
+		}
+		c := a.collect
+		if ratio := orderPitch(goal.Order, count); ratio != 1 {
+			if p, err := loadPitched(a.collectPath, ratio); err != nil {
+				fmt.Println(err)
+			} else {
+				c = p
+			}
+		}
+		a.pitched[key] = c
+	}
+}
+
+// loadPitched loads the WAV file at path with its pitch raised by ratio
+func loadPitched(path string, ratio float64) (*mixer.Chunk, error) {
+	w, err := readWAV(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "gojoystick-*.wav")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	err = w.Resample(ratio).Write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := mixer.LoadWAV(f.Name())
+	if c == nil {
+		return nil, fmt.Errorf("cannot load pitched %s: %s", path, mixer.GetError())
+	}
+	return c, nil
+}
+
+// orderPitch gives the pitch ratio for the goal with the given order out of count goals.  The pitch
+// rises evenly from the sound's own pitch for the first goal to an octave above for the last.
+func orderPitch(order, count int) float64 {
+	if count < 2 {
+		return 1
+	}
+	return math.Pow(2, float64(order)/float64(count-1))
+}
+
+// Close frees the sounds and stops SDL_mixer
+func (a *Audio) Close() {
+	if a == nil {
+		return
+	}
+	for key, c := range a.pitched {
+		if c != a.collect {
+			c.Free()
+		}
+		delete(a.pitched, key)
+	}
+	a.collect.Free()
+	mixer.CloseAudio()
+}
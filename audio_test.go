@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrderPitch(t *testing.T) {
+	tests := []struct {
+		order, count int
+		want         float64
+	}{
+		{0, 0, 1},
+		{0, 1, 1},
+		{0, 5, 1},
+		{2, 5, math.Sqrt2},
+		{4, 5, 2},
+	}
+	for _, tt := range tests {
+		if got := orderPitch(tt.order, tt.count); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("orderPitch(%d, %d) = %v, want %v", tt.order, tt.count, got, tt.want)
+		}
+	}
+	for order := 1; order < 8; order++ {
+		if orderPitch(order, 8) <= orderPitch(order-1, 8) {
+			t.Errorf("pitch does not rise from goal %d to %d", order-1, order)
+		}
+	}
+}
+
+func TestGoalsMadeForNextWord(t *testing.T) {
+	useConfig(t, Config{Words: []string{"AB", "CDE"}, CollectButton: -1})
+	g := NewGame([]Marker{{}}, letterGoals("AB", nil))
+	g.MakeGoals = letterGoals
+	made := -1
+	g.OnGoals = func(goals []*Goal, count int) {
+		made = count
+	}
+	g.nextWord()
+	if made != 3 {
+		t.Errorf("OnGoals got %d goals for the next word, want 3", made)
+	}
+}
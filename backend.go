@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An InputBackend delivers input events to the event loop, as SDL event values
+type InputBackend interface {
+	Events() <-chan interface{}
+}
+
+// sdlInput is the normal backend, the events SDL delivers
+type sdlInput struct{}
+
+func (sdlInput) Events() <-chan interface{} {
+	return sdl.Events
+}
+
+// FakeInput is a backend that makes up joystick events, from calls or from a script, so the event loop
+// can be driven without SDL or real joysticks.
+type FakeInput struct {
+	events chan interface{}
+}
+
+// Create a FakeInput with no events waiting
+func NewFakeInput() *FakeInput {
+	return &FakeInput{events: make(chan interface{}, 64)}
+}
+
+func (f *FakeInput) Events() <-chan interface{} {
+	return f.events
+}
+
+// Axis sends an axis moving on device dev
+func (f *FakeInput) Axis(dev, axis int, value int16) {
+	f.events <- sdl.JoyAxisEvent{Type: sdl.JOYAXISMOTION, Which: uint8(dev), Axis: uint8(axis), Value: value}
+}
+
+// Button sends a button being pressed or released on device dev
+func (f *FakeInput) Button(dev, button int, pressed bool) {
+	var state uint8
+	t := uint8(sdl.JOYBUTTONUP)
+	if pressed {
+		state = 1
+		t = sdl.JOYBUTTONDOWN
+	}
+	f.events <- sdl.JoyButtonEvent{Type: t, Which: uint8(dev), Button: uint8(button), State: state}
+}
+
+// Hat sends a hat moving on device dev
+func (f *FakeInput) Hat(dev, hat int, value uint8) {
+	f.events <- sdl.JoyHatEvent{Type: sdl.JOYHATMOTION, Which: uint8(dev), Hat: uint8(hat), Value: value}
+}
+
+// Quit sends a request to quit
+func (f *FakeInput) Quit() {
+	f.events <- sdl.QuitEvent{Type: sdl.QUIT}
+}
+
+// Forward passes on the events from another source, so SDL's keyboard and window events still arrive
+// while the joysticks are faked
+func (f *FakeInput) Forward(events <-chan interface{}) {
+	for e := range events {
+		f.events <- e
+	}
+}
+
+// Run plays a script of made up events, one per line:
+//
+//	axis <dev> <axis> <value>
+//	button <dev> <button> down|up
+//	hat <dev> <hat> <value>
+//	wait <duration>
+//	quit
+//
+// Blank lines and lines starting with # are skipped.
+func (f *FakeInput) Run(script io.Reader) error {
+	scanner := bufio.NewScanner(script)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if err := f.run(fields); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// run one line of a script
+func (f *FakeInput) run(fields []string) error {
+	args := make([]int, 0, 3)
+	numbers := func(n int) error {
+		if len(fields) != n+1 {
+			return fmt.Errorf("%s takes %d arguments", fields[0], n)
+		}
+		for _, s := range fields[1 : n+1] {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+			args = append(args, v)
+		}
+		return nil
+	}
+	switch fields[0] {
+	case "axis":
+		if err := numbers(3); err != nil {
+			return err
+		}
+		f.Axis(args[0], args[1], int16(args[2]))
+	case "button":
+		if len(fields) != 4 || (fields[3] != "down" && fields[3] != "up") {
+			return fmt.Errorf("expected button <dev> <button> down|up")
+		}
+		pressed := fields[3] == "down"
+		fields = fields[:3]
+		if err := numbers(2); err != nil {
+			return err
+		}
+		f.Button(args[0], args[1], pressed)
+	case "hat":
+		if err := numbers(3); err != nil {
+			return err
+		}
+		f.Hat(args[0], args[1], uint8(args[2]))
+	case "wait":
+		if len(fields) != 2 {
+			return fmt.Errorf("expected wait <duration>")
+		}
+		d, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return err
+		}
+		time.Sleep(d)
+	case "quit":
+		f.Quit()
+	default:
+		return fmt.Errorf("unknown event %q", fields[0])
+	}
+	return nil
+}
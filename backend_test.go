@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"strings"
+	"testing"
+	"time"
+)
+
+// drive plays script through a FakeInput and routes its events into the game the way the event loop
+// does.  It returns whether a quit came through.
+func drive(t *testing.T, g *Game, script string) bool {
+	fake := NewFakeInput()
+	if err := fake.Run(strings.NewReader(script)); err != nil {
+		t.Fatal(err)
+	}
+	var sticks Sticks
+	var inputs []Input
+	quit := false
+	for len(fake.Events()) > 0 {
+		switch e := (<-fake.Events()).(type) {
+		case sdl.QuitEvent:
+			quit = true
+		default:
+			if in, ok := sticks.Input(e); ok {
+				inputs = append(inputs, in)
+			}
+		}
+	}
+	Step(g, inputs, time.Second/60)
+	return quit
+}
+
+func TestFakeInputDrivesGame(t *testing.T) {
+	useConfig(t, Config{Speed: STEP, CollectButton: -1, TiltDevice: -1})
+	g := NewGame([]Marker{newMarker(0, 2), newMarker(1, 2)}, letterGoals("ABC", nil))
+	x, y := g.Markers[0].X, g.Markers[0].Y
+	quit := drive(t, g, `
+# player 1 goes right and player 2 holds a button
+axis 0 0 32767
+button 1 3 down
+hat 1 0 1
+`)
+	if quit {
+		t.Errorf("quit without a quit event")
+	}
+	if g.Markers[0].X <= x || g.Markers[0].Y != y {
+		t.Errorf("player 1 went from %d,%d to %d,%d, want right", x, y, g.Markers[0].X, g.Markers[0].Y)
+	}
+	if !g.Markers[1].ButtonHeld(3) {
+		t.Errorf("player 2's button is not held")
+	}
+	if g.Markers[1].Vhy >= 0 {
+		t.Errorf("player 2's hat is not up")
+	}
+	if !drive(t, g, "button 1 3 up\nquit\n") {
+		t.Errorf("the quit event did not come through")
+	}
+	if g.Markers[1].ButtonHeld(3) {
+		t.Errorf("player 2's button is still held")
+	}
+}
+
+func TestFakeInputScriptErrors(t *testing.T) {
+	for _, script := range []string{"jump 0", "axis 0 0", "axis 0 x 10", "button 0 1 sideways", "wait forever"} {
+		if err := NewFakeInput().Run(strings.NewReader(script)); err == nil {
+			t.Errorf("%q did not fail", script)
+		}
+	}
+}
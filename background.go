@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// A Background is a picture drawn behind everything instead of the theme's flat color.  A nil
+// Background is the flat color.
+type Background struct {
+	image  *sdl.Surface // the picture as loaded, in the display's format
+	scaled *sdl.Surface // the picture stretched to the surface last filled
+}
+
+// LoadBackground loads the picture from config.Background, or the "background" asset when that is
+// not set.  It gives nil if neither is given.  The video mode has to be set first.
+func LoadBackground(assets *Assets) (*Background, error) {
+	var img *sdl.Surface
+	if config.Background != "" {
+		if img = sdl.Load(config.Background); img == nil {
+			return nil, fmt.Errorf("cannot load background %s: %s", config.Background, sdl.GetError())
+		}
+		defer img.Free()
+	} else if assets.Path("background") != "" {
+		// the assets keep their own copy
+		if img = assets.Image("background"); img == nil {
+			return nil, nil
+		}
+	} else {
+		return nil, nil
+	}
+	return &Background{image: img.DisplayFormatAlpha()}, nil
+}
+
+// Fill covers the surface with the background, plain black in the high contrast theme
+func (b *Background) Fill(s *sdl.Surface) {
+	if config.HighContrast {
+		s.FillRect(nil, CONTRAST_BACKGROUND)
+		return
+	}
+	if b == nil || b.image == nil {
+		s.FillRect(nil, config.BackgroundColor)
+		return
+	}
+	if b.scaled == nil || b.scaled.W != s.W || b.scaled.H != s.H {
+		if b.scaled != nil && b.scaled != b.image {
+			b.scaled.Free()
+		}
+		b.scaled = b.image
+		if b.image.W != s.W || b.image.H != s.H {
+			b.scaled = scaleSurface(b.image, int(s.W), int(s.H))
+		}
+	}
+	if b.scaled == nil {
+		s.FillRect(nil, config.BackgroundColor)
+		return
+	}
+	s.Blit(nil, b.scaled, nil)
+}
+
+// Free releases the picture
+func (b *Background) Free() {
+	if b == nil {
+		return
+	}
+	if b.scaled != nil && b.scaled != b.image {
+		b.scaled.Free()
+	}
+	if b.image != nil {
+		b.image.Free()
+	}
+	b.scaled, b.image = nil, nil
+}
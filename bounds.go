@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"strconv"
+	"strings"
+)
+
+// What happens when a marker reaches the edge of its area
+const (
+	EDGE_WRAP   = "wrap"   // it comes back on the other side
+	EDGE_WALL   = "wall"   // it stops against the edge
+	EDGE_BOUNCE = "bounce" // it bounces back off the edge
+)
+
+// keepInBounds keeps the marker in its area, as config.Edges says
+func (m *Marker) keepInBounds() {
+	x, y, w, h := m.Area()
+	switch config.Edges {
+	case EDGE_WALL, EDGE_BOUNCE:
+		var hitX, hitY bool
+		m.X, hitX = reflectIn(m.X, x+markerW/2, x+w-markerW/2, config.Edges == EDGE_BOUNCE)
+		m.Y, hitY = reflectIn(m.Y, y+markerH/2, y+h-markerH/2, config.Edges == EDGE_BOUNCE)
+		if config.Edges == EDGE_BOUNCE && hitX {
+			m.Vax, m.targetX, m.Vhx, m.velX = -m.Vax, -m.targetX, -m.Vhx, -m.velX
+		}
+		if config.Edges == EDGE_BOUNCE && hitY {
+			m.Vay, m.targetY, m.Vhy, m.velY = -m.Vay, -m.targetY, -m.Vhy, -m.velY
+		}
+	default:
+		m.X = x + wrap(m.X-x, w)
+		m.Y = y + wrap(m.Y-y, h)
+	}
+}
+
+// reflectIn keeps v between lo and hi.  Past an end it is stopped there, or with reflect mirrored back
+// by as far as it went past.  hit reports whether it went past.
+func reflectIn(v, lo, hi int, reflect bool) (int, bool) {
+	if v >= lo && v <= hi {
+		return v, false
+	}
+	if reflect {
+		if v < lo {
+			v = 2*lo - v
+		} else {
+			v = 2*hi - v
+		}
+	}
+	// clamp, which also catches a reflection so far it went past the other end
+	if v < lo {
+		v = lo
+	} else if v > hi {
+		v = hi
+	}
+	return v, true
+}
+
+// playerBounds gives the area player i of n is kept in.  An explicit zone from config.Zones wins,
+// then with config.Split each player gets an equal vertical strip of the screen.  Otherwise the
+// result is empty, meaning the whole screen.
+func playerBounds(i, n int) sdl.Rect {
+	if r, ok := config.Zones[i]; ok {
+		return r
+	}
+	if config.Split && n > 1 {
+		w, h := worldSize()
+		w /= n
+		return sdl.Rect{int16(i * w), 0, uint16(w), uint16(h)}
+	}
+	return sdl.Rect{}
+}
+
+// parseZones reads per player zones written as "player=x,y,w,h" separated by semicolons
+func parseZones(s string) (map[int]sdl.Rect, error) {
+	zones := make(map[int]sdl.Rect)
+	for _, z := range strings.Split(s, ";") {
+		if z = strings.TrimSpace(z); z == "" {
+			continue
+		}
+		parts := strings.SplitN(z, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("bad zone %q, expected player=x,y,w,h", z)
+		}
+		player, err := strconv.Atoi(parts[0])
+		if err != nil || player < 0 {
+			return nil, fmt.Errorf("bad player in zone %q", z)
+		}
+		var v [4]int
+		nums := strings.Split(parts[1], ",")
+		if len(nums) != 4 {
+			return nil, fmt.Errorf("bad zone %q, expected player=x,y,w,h", z)
+		}
+		for j, n := range nums {
+			if v[j], err = strconv.Atoi(strings.TrimSpace(n)); err != nil || v[j] < 0 {
+				return nil, fmt.Errorf("bad number in zone %q", z)
+			}
+		}
+		if w, h := worldSize(); v[2] == 0 || v[3] == 0 || v[0]+v[2] > w || v[1]+v[3] > h {
+			return nil, fmt.Errorf("zone %q is not on the playfield", z)
+		}
+		zones[player] = sdl.Rect{int16(v[0]), int16(v[1]), uint16(v[2]), uint16(v[3])}
+	}
+	return zones, nil
+}
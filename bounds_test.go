@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"testing"
+)
+
+func TestReflectIn(t *testing.T) {
+	tests := []struct {
+		v       int
+		reflect bool
+		want    int
+		hit     bool
+	}{
+		{50, false, 50, false},
+		{10, false, 10, false},
+		{5, false, 10, true},
+		{5, true, 15, true},
+		{105, false, 100, true},
+		{105, true, 95, true},
+		{300, true, 10, true},
+	}
+	for _, tt := range tests {
+		got, hit := reflectIn(tt.v, 10, 100, tt.reflect)
+		if got != tt.want || hit != tt.hit {
+			t.Errorf("reflectIn(%d, 10, 100, %v) = %d, %v, want %d, %v", tt.v, tt.reflect, got, hit, tt.want, tt.hit)
+		}
+	}
+}
+
+func TestParseZones(t *testing.T) {
+	useConfig(t, Config{})
+	zones, err := parseZones("0=0,0,512,768; 1=512,0,512,768")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (sdl.Rect{512, 0, 512, 768}); len(zones) != 2 || zones[1] != want {
+		t.Errorf("parseZones gave %v", zones)
+	}
+	for _, s := range []string{"0", "x=0,0,1,1", "0=0,0,1", "0=0,0,-1,1", "0=0,0,0,10", "0=1000,0,100,100"} {
+		if _, err := parseZones(s); err == nil {
+			t.Errorf("parseZones(%q) did not fail", s)
+		}
+	}
+}
+
+func TestPlayerBounds(t *testing.T) {
+	useConfig(t, Config{Split: true, Zones: map[int]sdl.Rect{1: {10, 20, 30, 40}}})
+	if got, want := playerBounds(0, 3), (sdl.Rect{0, 0, WIDTH / 3, HEIGHT}); got != want {
+		t.Errorf("split bounds %v, want %v", got, want)
+	}
+	if got, want := playerBounds(1, 3), (sdl.Rect{10, 20, 30, 40}); got != want {
+		t.Errorf("zone bounds %v, want %v", got, want)
+	}
+}
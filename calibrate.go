@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"os"
+)
+
+// Calibration steps
+const (
+	CAL_RANGE  = iota // the sticks are moved all the way around
+	CAL_CENTER        // the sticks are let go
+	CAL_DONE
+)
+
+// AxisCal is the range of an axis, as seen while calibrating
+type AxisCal struct {
+	Min, Center, Max int16
+}
+
+// Normalize stretches a value in the calibrated range over the whole axis range, with the center at 0
+func (c AxisCal) Normalize(v int16) int16 {
+	var out int
+	if v >= c.Center {
+		if span := int(c.Max) - int(c.Center); span > 0 {
+			out = (int(v) - int(c.Center)) * 32767 / span
+		}
+	} else {
+		if span := int(c.Center) - int(c.Min); span > 0 {
+			out = (int(v) - int(c.Center)) * 32768 / span
+		}
+	}
+	if out > 32767 {
+		out = 32767
+	}
+	if out < -32768 {
+		out = -32768
+	}
+	return int16(out)
+}
+
+// Calibrations maps joystick names to the calibration of their axes.  SDL 1.2 does not give joysticks
+// a GUID, so the name is the best there is.  Identical sticks share a calibration.
+type Calibrations map[string][]*AxisCal
+
+// loadCalibrations reads the calibrations saved by save.  A missing file is the same as an empty one.
+func loadCalibrations(path string) (Calibrations, error) {
+	cal := make(Calibrations)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cal, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &cal); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return cal, nil
+}
+
+// save the calibrations as JSON
+func (c Calibrations) save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Normalize an axis value of the named joystick, axes without a calibration are left alone
+func (c Calibrations) Normalize(name string, axis int, v int16) int16 {
+	axes := c[name]
+	if axis >= len(axes) || axes[axis] == nil {
+		return v
+	}
+	return axes[axis].Normalize(v)
+}
+
+// A Calibrator runs the calibration screen.  First the sticks are moved all the way around to find the
+// ends of each axis, then they are let go to find the centers.  A button press moves on to the next
+// step.
+type Calibrator struct {
+	Step   int // one of the CAL_* constants
+	Prompt *Label
+	seen   map[[2]int]*AxisCal // by device and axis
+	last   map[[2]int]int16
+}
+
+// Start calibrating
+func NewCalibrator(font *ttf.Font) *Calibrator {
+	c := &Calibrator{
+		Prompt: &Label{Font: font, Color: config.TextColor},
+		seen:   make(map[[2]int]*AxisCal),
+		last:   make(map[[2]int]int16),
+	}
+	c.prompt("Move each stick all the way around, then press a button")
+	return c
+}
+
+func (c *Calibrator) prompt(text string) {
+	c.Prompt.SetText(text)
+	c.Prompt.X = (frameW - int(c.Prompt.Rect().W)) / 2
+	c.Prompt.Y = (frameH - int(c.Prompt.Rect().H)) / 2
+}
+
+// Axis records an axis moving
+func (c *Calibrator) Axis(dev, axis int, v int16) {
+	key := [2]int{dev, axis}
+	c.last[key] = v
+	if c.Step != CAL_RANGE {
+		return
+	}
+	cal, ok := c.seen[key]
+	if !ok {
+		cal = &AxisCal{Min: v, Max: v}
+		c.seen[key] = cal
+	}
+	if v < cal.Min {
+		cal.Min = v
+	}
+	if v > cal.Max {
+		cal.Max = v
+	}
+}
+
+// Button moves on to the next step.  It returns true when calibrating is finished.
+func (c *Calibrator) Button() bool {
+	switch c.Step {
+	case CAL_RANGE:
+		c.Step = CAL_CENTER
+		c.prompt("Let go of the sticks, then press a button")
+	case CAL_CENTER:
+		for key, cal := range c.seen {
+			cal.Center = c.last[key]
+		}
+		c.Step = CAL_DONE
+	}
+	return c.Step == CAL_DONE
+}
+
+// Merge the axes that were moved into cal, names gives the name of each device
+func (c *Calibrator) Merge(cal Calibrations, names []string) {
+	for key, ac := range c.seen {
+		dev, axis := key[0], key[1]
+		if dev >= len(names) || ac.Max <= ac.Min {
+			continue
+		}
+		axes := cal[names[dev]]
+		for len(axes) <= axis {
+			axes = append(axes, nil)
+		}
+		axes[axis] = ac
+		cal[names[dev]] = axes
+	}
+}
+
+// Free the prompt
+func (c *Calibrator) Free() {
+	c.Prompt.Free()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	// a worn stick that only reaches -20000 to 24000 and rests at 2000
+	c := AxisCal{Min: -20000, Center: 2000, Max: 24000}
+	tests := []struct {
+		v, want int16
+	}{
+		{2000, 0},
+		{24000, 32767},
+		{30000, 32767},
+		{13000, 16383},
+		{-20000, -32768},
+		{-32768, -32768},
+		{-9000, -16384},
+	}
+	for _, tt := range tests {
+		if got := c.Normalize(tt.v); got != tt.want {
+			t.Errorf("Normalize(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+	// a calibration that never saw the stick move leaves it at the center
+	if got := (AxisCal{}).Normalize(1234); got != 0 {
+		t.Errorf("empty calibration gave %d, want 0", got)
+	}
+}
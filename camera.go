@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// Camera modes
+const (
+	CAMERA_OFF      = "off"      // show the playfield as is
+	CAMERA_PLAYER   = "player"   // keep player 1 in the middle of the screen
+	CAMERA_CENTROID = "centroid" // keep the middle of all the players in the middle of the screen
+)
+
+// the most screens wide and high -world can make the playfield.  The whole playfield is drawn to one
+// 32 bit surface, which at 3 screens each way is already 27 MiB.
+const WORLD_MAX = 3
+
+// A Camera decides which part of the playfield is shown.  The world point X, Y is kept at the center of
+// the screen.  The playfield wraps around, so the view does too.  Collisions are unaffected, they are
+// always done in world coordinates.  The playfield can be bigger than the screen with -world.
+type Camera struct {
+	X, Y int
+}
+
+// Follow points the camera at the markers according to mode
+func (c *Camera) Follow(markers []Marker, mode string) {
+	if len(markers) == 0 {
+		return
+	}
+	switch mode {
+	case CAMERA_PLAYER:
+		c.X, c.Y = markers[0].X, markers[0].Y
+	case CAMERA_CENTROID:
+		x, y := 0, 0
+		for _, m := range markers {
+			x += m.X
+			y += m.Y
+		}
+		c.X, c.Y = x/len(markers), y/len(markers)
+	}
+}
+
+// worldSize gives the size of the playfield, config.World screens each way
+func worldSize() (w, h int) {
+	if config.World < 1 {
+		return frameW, frameH
+	}
+	return frameW * config.World, frameH * config.World
+}
+
+// Offset returns how far world coordinates are shifted to become screen coordinates.  Both values are
+// in the range of the playfield's size.
+func (c Camera) Offset() (dx, dy int) {
+	w, h := worldSize()
+	dx = wrap(frameW/2-c.X, w)
+	dy = wrap(frameH/2-c.Y, h)
+	return dx, dy
+}
+
+// WorldToScreen converts a world position to where it is shown on screen
+func (c Camera) WorldToScreen(x, y int) (int, int) {
+	if config.Camera == CAMERA_OFF {
+		return x, y
+	}
+	dx, dy := c.Offset()
+	w, h := worldSize()
+	return wrap(x+dx, w), wrap(y+dy, h)
+}
+
+// ScreenToWorld converts a position on screen to the world position shown there
+func (c Camera) ScreenToWorld(x, y int) (int, int) {
+	if config.Camera == CAMERA_OFF {
+		return x, y
+	}
+	dx, dy := c.Offset()
+	w, h := worldSize()
+	return wrap(x-dx, w), wrap(y-dy, h)
+}
+
+// Blit draws the world surface onto the screen as seen by the camera.  As the view wraps this takes up
+// to four pieces, SDL clips the parts that are off screen.
+func (c Camera) Blit(screen, world *sdl.Surface) {
+	dx, dy := c.Offset()
+	w, h := worldSize()
+	for _, x := range []int{dx, dx - w} {
+		for _, y := range []int{dy, dy - h} {
+			screen.Blit(&sdl.Rect{int16(x), int16(y), 0, 0}, world, nil)
+		}
+	}
+}
+
+// wrap v into the range [0, size)
+func wrap(v, size int) int {
+	v %= size
+	if v < 0 {
+		v += size
+	}
+	return v
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCameraWorldToScreen(t *testing.T) {
+	tests := []struct {
+		name         string
+		world        int
+		mx, my       int // the followed marker
+		x, y         int // a world position
+		wantX, wantY int // where it is on the screen
+	}{
+		{"marker in the middle", 1, WIDTH / 2, HEIGHT / 2, 100, 200, 100, 200},
+		{"marker itself", 1, 100, 100, 100, 100, WIDTH / 2, HEIGHT / 2},
+		{"to the right of the marker", 1, 100, 100, 150, 130, WIDTH/2 + 50, HEIGHT/2 + 30},
+		{"across the wrap", 1, 10, 10, WIDTH - 10, HEIGHT - 10, WIDTH/2 - 20, HEIGHT/2 - 20},
+		{"bigger world", 3, 2000, 1500, 2100, 1400, WIDTH/2 + 100, HEIGHT/2 - 100},
+		{"bigger world across the wrap", 3, 10, 10, 3*WIDTH - 10, 10, WIDTH/2 - 20, HEIGHT / 2},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{Camera: CAMERA_PLAYER, World: tt.world})
+		var c Camera
+		c.Follow([]Marker{{X: tt.mx, Y: tt.my}}, CAMERA_PLAYER)
+		x, y := c.WorldToScreen(tt.x, tt.y)
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("%s: %d,%d shown at %d,%d, want %d,%d", tt.name, tt.x, tt.y, x, y, tt.wantX, tt.wantY)
+		}
+		if wx, wy := c.ScreenToWorld(x, y); wx != tt.x || wy != tt.y {
+			t.Errorf("%s: %d,%d back in the world is %d,%d", tt.name, x, y, wx, wy)
+		}
+	}
+}
+
+func TestCameraCentroid(t *testing.T) {
+	var c Camera
+	c.Follow([]Marker{{X: 100, Y: 100}, {X: 300, Y: 500}}, CAMERA_CENTROID)
+	if c.X != 200 || c.Y != 300 {
+		t.Errorf("camera at %d,%d, want 200,300", c.X, c.Y)
+	}
+}
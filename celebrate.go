@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"time"
+)
+
+const (
+	// how long a celebration lasts
+	CELEBRATE_TIME = time.Second
+	// how thick the flashing border is
+	FLASH_WIDTH = 24
+	// how much longer and thicker the celebration for finishing a round is
+	BIG_CELEBRATION = 3
+)
+
+// the bright colors celebrations are drawn in
+var celebrateColors = []uint32{0x00ffdd00, 0x00ff44aa, 0x0044ddff, 0x0066ff44}
+
+// Celebrate starts a celebration, the screen border flashes for a while.  A big celebration lasts
+// longer and has a thicker border.
+func (g *Game) Celebrate(big bool) {
+	g.bigCelebration = big
+	if big {
+		g.celebrateUntil = g.Clock + BIG_CELEBRATION*CELEBRATE_TIME
+	} else {
+		g.celebrateUntil = g.Clock + CELEBRATE_TIME
+	}
+}
+
+// collected is called when a goal has been collected.  It counts the collections and celebrates
+// every config.CelebrateEvery of them, and always (bigger) when the last goal is collected.
+func (g *Game) collected() {
+	g.Collected++
+	if g.OnCollect != nil {
+		g.OnCollect(g.Goals[g.CurGoal], len(g.Goals))
+	}
+	g.event("collect " + g.Goals[g.CurGoal].Text)
+	g.burst(g.Goals[g.CurGoal])
+	last := g.CurGoal == len(g.Goals)-1
+	if celebrate, big := shouldCelebrate(g.Collected, config.CelebrateEvery, last); celebrate {
+		g.Celebrate(big)
+	}
+}
+
+// shouldCelebrate decides whether the collection of the count'th goal gets a celebration, and whether
+// it is a big one.  Finishing the last goal always gets a big celebration.  every <= 0 leaves only that.
+func shouldCelebrate(count, every int, last bool) (celebrate, big bool) {
+	if last {
+		return true, true
+	}
+	if every <= 0 {
+		return false, false
+	}
+	return count%every == 0, false
+}
+
+// Is a celebration going on
+func (g *Game) Celebrating() bool {
+	return g.Clock < g.celebrateUntil
+}
+
+// A Flash is a Drawable that draws a colored border around the screen
+type Flash struct {
+	Color uint32
+	Width int // thickness of the border
+}
+
+// Get the bounding rectangle of the flash, the whole screen
+func (f Flash) Rect() *sdl.Rect {
+	return frameRect()
+}
+
+// Draw the border
+func (f Flash) Draw(screen *sdl.Surface) {
+	w := f.Width
+	screen.FillRect(&sdl.Rect{0, 0, uint16(frameW), uint16(w)}, f.Color)
+	screen.FillRect(&sdl.Rect{0, int16(frameH - w), uint16(frameW), uint16(w)}, f.Color)
+	screen.FillRect(&sdl.Rect{0, 0, uint16(w), uint16(frameH)}, f.Color)
+	screen.FillRect(&sdl.Rect{int16(frameW - w), 0, uint16(w), uint16(frameH)}, f.Color)
+}
+
+// CelebrationFlash gives the flash to draw, cycling through bright colors
+func (g *Game) CelebrationFlash() Flash {
+	f := Flash{Color: celebrateColors[(g.Clock/(time.Second/8))%time.Duration(len(celebrateColors))], Width: FLASH_WIDTH}
+	if g.bigCelebration {
+		f.Width *= BIG_CELEBRATION
+	}
+	return f
+}
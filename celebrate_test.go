@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestShouldCelebrate(t *testing.T) {
+	tests := []struct {
+		count, every  int
+		last          bool
+		want, wantBig bool
+	}{
+		{1, 1, false, true, false},
+		{2, 1, false, true, false},
+		{1, 3, false, false, false},
+		{3, 3, false, true, false},
+		{6, 3, false, true, false},
+		{7, 3, false, false, false},
+		{7, 3, true, true, true},
+		{5, 0, false, false, false},
+		{5, 0, true, true, true},
+		{5, -1, false, false, false},
+	}
+	for _, tt := range tests {
+		got, big := shouldCelebrate(tt.count, tt.every, tt.last)
+		if got != tt.want || big != tt.wantBig {
+			t.Errorf("shouldCelebrate(%d, %d, %v) = %v, %v, want %v, %v", tt.count, tt.every, tt.last, got, big, tt.want, tt.wantBig)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+)
+
+// chooseSticks shows the joysticks and lets the players pick which of them play.  A number key, or a
+// button on the stick itself, switches a stick between playing and not.  Return (or space) starts the
+// game.  It returns false if the program should quit instead.
+func chooseSticks(screen *sdl.Surface, font *ttf.Font, sticks *Sticks) bool {
+	text := config.TextColor
+	title := &Label{Font: font, Color: text, X: 40, Y: 40}
+	title.SetText("Press a button on each controller to switch it on or off, then press Return")
+	defer title.Free()
+	lines := make([]*Label, len(sticks.Names))
+	for i := range lines {
+		lines[i] = &Label{Font: font, Color: text, X: 60, Y: 100 + i*30}
+		defer lines[i].Free()
+	}
+
+	toggle := func(i int) {
+		if i >= 0 && i < len(sticks.Names) {
+			sticks.Skip[i] = !sticks.Skip[i]
+		}
+	}
+	for {
+		screen.FillRect(nil, config.BackgroundColor)
+		title.Draw(screen)
+		for i, l := range lines {
+			state := "playing"
+			if sticks.Skip[i] {
+				state = "off"
+			}
+			l.SetText(fmt.Sprintf("%d. %s - %s", i+1, sticks.Names[i], state))
+			l.Draw(screen)
+		}
+		screen.Flip()
+
+		switch e := (<-sdl.Events).(type) {
+		case sdl.QuitEvent:
+			return false
+		case sdl.KeyboardEvent:
+			if e.State == 0 {
+				break
+			}
+			switch {
+			case e.Keysym.Sym == sdl.K_ESCAPE || e.Keysym.Sym == sdl.K_q:
+				return false
+			case e.Keysym.Sym == sdl.K_RETURN || e.Keysym.Sym == sdl.K_SPACE:
+				return true
+			case e.Keysym.Sym >= sdl.K_1 && e.Keysym.Sym <= sdl.K_9:
+				toggle(int(e.Keysym.Sym - sdl.K_1))
+			}
+		case sdl.JoyButtonEvent:
+			if e.State > 0 {
+				toggle(int(e.Which))
+			}
+		}
+	}
+}
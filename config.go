@@ -0,0 +1,475 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the options that control a session.  They are filled in from the command line.
+type Config struct {
+	CSVPath         string           // file to write the per player statistics to when the session ends
+	ShowPresses     bool             // show the per player button press counters
+	HUD             bool             // show the scores and progress along the bottom of the screen
+	SelfTest        bool             // print what SDL reports about each joystick at startup
+	Fullscreen      bool             // fill the display instead of opening a window
+	Display         int              // the monitor the fullscreen game covers, -1 for the one SDL picks
+	WindowPos       string           // where the window opens on the desktop, as x,y or center
+	Hotplug         time.Duration    // how often to look for joysticks being plugged in or out, 0 to never
+	Inputs          InputMap         // which player each joystick's movement and buttons control
+	Deadzones       Deadzones        // how far each stick axis must move before it counts
+	Invert          Inversions       // stick axes that point the wrong way
+	FlipY           bool             // turn up and down around on every stick
+	Curve           string           // how stick position turns into speed, one of the CURVE_* constants
+	Smoothing       float64          // how much of the old stick velocity is kept each update, 0 for no smoothing
+	DriftTime       time.Duration    // how long a stick rests off center before that is taken as drift, 0 to never
+	DriftPath       string           // where the learned drift is kept
+	TiltDevice      int              // joystick whose axes are the tilt of a controller, -1 for none
+	TiltSensitivity float64          // how strongly tilting moves the marker
+	TiltRecenter    int              // button of the tilt device that makes the current tilt count as level, -1 for none
+	TiltPlayer      int              // player the tilt device steers
+	CurveExponent   float64          // shape of the power and exponential curves
+	CalibrationPath string           // where the stick calibrations are kept
+	ControllerDB    string           // SDL game controller database giving sticks logical controls
+	AxisRoles       map[int]int      // logical axis of each raw axis, for sticks not in ControllerDB
+	TriggerBoost    float64          // speed multiplier with a trigger squeezed all the way
+	TwoHands        bool             // the right stick drives a second marker for the same player
+	ChooseSticks    bool             // pick the joysticks that play on a screen at startup
+	RecordPath      string           // file to record the input of each simulation step to
+	ReplayPath      string           // recording to play back instead of the joysticks
+	VideoPath       string           // GIF or directory of PNGs to record the screen to
+	VideoRate       int              // most frames a second recorded to VideoPath
+	VideoShrink     int              // how many times smaller than the screen video frames are
+	FakeInput       string           // script of made up joystick events to play
+	Evdev           string           // read the joysticks from these event devices (or "auto") instead of through SDL
+	Rumble          bool             // shake the controller that collected a goal, evdev only
+	ButtonsPath     string           // where the button actions set on the remap screen are kept
+	ProfilesPath    string           // JSON file of per joystick profiles
+	Zones           map[int]sdl.Rect // areas particular players are kept in
+	KeyboardPlayer  int              // player the keyboard controls, -1 for none unless there are no joysticks
+	MousePlayer     int              // player that follows the mouse pointer, -1 for none
+	TouchPlayer     int              // player dragged around the touchscreen, -1 for none
+	PhoneAddr       string           // address to serve the phone gamepad page on, empty for none
+	PhonePlayer     int              // player a phone controls unless its page asks for another
+	MidiPath        string           // raw MIDI device to read a controller from
+	MidiPlayer      int              // player the MIDI controller moves
+	MidiCC          map[int]int      // axis each MIDI control change moves
+	Split           bool             // give each player an equal strip of the screen
+	AssetDir        string           // directory holding the asset manifest and files
+	Background      string           // picture drawn behind the game, instead of the "background" asset
+	ControlPath     string           // unix socket other programs can control the session through
+	UpdateRate      int              // game updates per second, independent of the frame rate
+	FrameRate       int              // frames drawn per second at most, the markers are smoothed between updates
+	VSync           bool             // ask for flips to wait for the display's refresh
+	DirtyRects      bool             // only redraw the parts of the screen that changed
+	Seed            int64            // seed for the random goal placement
+	TestMode        bool             // start in test mode, with the assists turned off
+	IdleTimeout     time.Duration    // pause and start over after this long without any input, 0 to never
+	Squash          float64          // how much the markers stretch when moving, 0 for not at all
+	Filter          string           // post processing applied to each frame, one of the FILTER_* constants
+	ShadowOffset    int              // how far (in pixels) the goal's drop shadow is offset, 0 for none
+	ShadowColor     sdl.Color        // color of the drop shadow
+	Reveal          string           // how a new goal appears, one of the REVEAL_* constants
+	RevealTime      time.Duration    // how long the reveal animation lasts
+	RevealCollide   bool             // goals can be collected while they are still being revealed
+	PitchByOrder    bool             // raise the pitch of the collection sound through the goal sequence
+	CelebrateEvery  int              // celebrate every this many goals collected, finishing a round is always celebrated
+	Particles       bool             // collected goals burst into particles
+	Pulse           bool             // the goal to collect pulses so it is easy to spot
+	Trail           int              // how many of each marker's last positions are left behind as a fading trail, 0 for none
+	Mode            string           // which game to play, one of the MODE_* constants
+	Camera          string           // what the view follows, one of the CAMERA_* constants
+	World           int              // how many screens wide and high the playfield is
+	Minimap         bool             // show a map of the whole playfield when it is bigger than the screen
+	Hint            bool             // show an arrow by each marker pointing to the current goal
+	HintDistance    float64          // how far (in pixels) the goal has to be for the hint arrow to show
+	Words           []string         // words to spell in order instead of collecting the alphabet
+	Sprites         []string         // asset names of the images drawn for each player's marker
+	Palette         string           // the marker colors, one of the PALETTE_* constants
+	Patterns        bool             // draw a different pattern on each player's marker
+	HighContrast    bool             // black background, yellow goals with thick outlines and bordered markers
+	Debug           bool             // start with the debug overlay showing
+	ShowSticks      bool             // start with each controller's stick, hat and buttons showing
+	BackgroundColor uint32           // the screen behind everything, from the theme
+	MarkerColors    []uint32         // the first players' marker colors from the theme, before the palette's
+	GoalColor       sdl.Color        // the goal text, from the theme
+	TextColor       sdl.Color        // labels, menus and the HUD, from the theme
+	Font            string           // font file from -font or the theme, instead of the "font" asset
+	GoalSet         string           // the built in goals to collect when there are no words, one of the GOALS_* constants
+	LayoutPath      string           // layout file to load the goals from instead of placing them at random
+	MazePath        string           // maze file for maze mode
+	LayoutOut       string           // where F2 saves the current goal layout
+	EndPolicy       string           // what happens after the last goal, one of the END_* constants
+	FinishScreen    bool             // show the finish screen after the last goal instead of starting over straight away
+
+	// keys the teacher can use to advance the goal, restart the round or celebrate
+	TeacherKeys                                      bool
+	TeacherAdvance, TeacherRestart, TeacherCelebrate string
+
+	// holding TractorButton pulls a marker within TractorRange pixels of the goal towards it by
+	// TractorStrength pixels a frame
+	TractorButton   int
+	TractorRange    float64
+	TractorStrength float64
+
+	// goal placement, new positions keep AvoidDistance pixels away from the last AvoidHistory positions
+	AvoidDistance float64
+	AvoidHistory  int
+
+	// the settings -difficulty picks, see difficulty.go
+	Difficulty string
+	GoalSize   int     // font size the goals are drawn at
+	Speed      float64 // how far (in pixels) a marker moves in an update at full stick
+	GoalCount  int     // how many of the goals to use, 0 for all of them
+
+	// adaptive difficulty: goals collected quicker than AdaptFast raise the level by AdaptStep and ones
+	// slower than AdaptSlow lower it.  At the top level goals are AdaptMinScale of their size and
+	// appear at least AdaptDistance pixels from the markers.
+	Adapt                bool
+	AdaptFast, AdaptSlow time.Duration
+	AdaptStep            float64
+	AdaptMinScale        float64
+	AdaptDistance        float64
+
+	// with Inertia the stick accelerates the markers instead of setting their speed, and Friction
+	// slows them down
+	Inertia      bool
+	Acceleration float64
+	Friction     float64
+
+	CollectButton    int           // button that has to be pressed on a goal to collect it, -1 to collect by touch
+	Dwell            int           // how many updates in a row a marker has to stay on a goal to collect it
+	TrackTime        time.Duration // how long the tracking exercise lasts
+	TrackSpeed       float64       // how fast the dot moves in the tracking exercise
+	Edges            string        // what markers do at the edge of their area, one of the EDGE_* constants
+	ClearButton      int           // button that clears the canvas in paint mode
+	ScreenshotButton int           // button that saves a screenshot, -1 for none
+	ScreenshotDir    string        // where screenshots are saved
+	ColorButton      int           // button that changes a player's color in paint mode
+	Coop             bool          // goals are only collected with every player on them together
+	GoalSpeed        float64       // how fast (in pixels a second) goals drift around the screen, 0 for not at all
+
+	Obstacles       int    // how many obstacles to put on the screen
+	ObstaclePenalty string // what touching an obstacle does, one of the OBSTACLE_* constants
+
+	// ordered mode options
+	Preview      int     // how many goals after the current one are shown
+	PushStrength float64 // how far (in pixels) a marker is pushed away from the wrong goal, 0 for no push
+
+	// whack-a-mole options
+	GridCols, GridRows int           // the grid the goals are placed on
+	SpawnInterval      time.Duration // pause before the next goal appears
+	GoalTimeout        time.Duration // how long a goal waits before moving elsewhere
+	MissPenalty        bool          // lose a point when a goal times out
+}
+
+var config Config
+
+// parseFlags reads the command line into config
+func parseFlags() {
+	flag.StringVar(&config.CSVPath, "csv", "", "write per player session statistics to this CSV file")
+	flag.BoolVar(&config.ShowPresses, "presses", false, "show a button press counter for each player")
+	flag.BoolVar(&config.HUD, "hud", true, "show the scores, the next goal and progress along the bottom of the screen")
+	flag.StringVar(&config.Palette, "palette", PALETTE_CLASSIC, "marker colors: classic, okabe-ito or tol (both colorblind safe) or contrast")
+	themePath := flag.String("theme", "", "JSON file with the colors and font to use")
+	font := flag.String("font", "", "font file to use instead of font.ttf (or the theme's)")
+	flag.BoolVar(&config.ShowSticks, "show-sticks", false, "show each controller's stick, hat, triggers and buttons (F11 switches it)")
+	flag.BoolVar(&config.Debug, "debug", false, "show the frame rate, raw joystick values and event counts (F12 switches it)")
+	flag.BoolVar(&config.HighContrast, "high-contrast", false, "start with the high contrast theme for low vision (F1 switches it)")
+	flag.BoolVar(&config.Patterns, "patterns", false, "draw a different pattern on each player's marker, so they can be told apart without color")
+	sprites := flag.String("sprites", "", "images to draw for the players' markers instead of squares, comma separated asset names in player order")
+	flag.IntVar(&config.Display, "display", -1, "monitor (counted from 0) to show the fullscreen game on, -1 for SDL's choice")
+	flag.StringVar(&config.WindowPos, "window-pos", "", "where to open the window, as x,y on the desktop (use it to pick a monitor) or center")
+	flag.BoolVar(&config.Fullscreen, "fullscreen", false, "fill the display instead of opening a window (alt+enter or the guide button switches)")
+	flag.BoolVar(&config.SelfTest, "selftest", false, "print the axes, buttons, hats and balls of each joystick at startup")
+	flag.DurationVar(&config.Hotplug, "hotplug", 2*time.Second, "how often to look for joysticks being plugged in or out, 0 to never")
+	flag.BoolVar(&config.TeacherKeys, "teacher", true, "enable the teacher override keys")
+	flag.StringVar(&config.TeacherAdvance, "teacher-advance", "f5", "key that moves on to the next goal")
+	flag.StringVar(&config.TeacherRestart, "teacher-restart", "f6", "key that restarts the round")
+	flag.StringVar(&config.TeacherCelebrate, "teacher-celebrate", "f7", "key that starts a celebration")
+	flag.IntVar(&config.FrameRate, "frame-rate", 60, "frames drawn per second while anything moves, lower it on slow machines such as a Raspberry Pi")
+	flag.BoolVar(&config.DirtyRects, "dirty-rects", false, "only redraw and send the parts of the screen that changed, for slow machines")
+	flag.BoolVar(&config.VSync, "vsync", false, "double buffer the screen so each frame waits for the display's refresh, where the video driver supports it")
+	flag.IntVar(&config.UpdateRate, "update-rate", 30, "game updates per second, drawing is smoothed between updates")
+	flag.IntVar(&config.Trail, "trail", 0, "leave a fading trail of each marker's last this many positions (up to 240), 0 for none")
+	flag.BoolVar(&config.Pulse, "pulse", true, "make the goal to collect gently pulse in size, -pulse=false for no animation")
+	flag.BoolVar(&config.Particles, "particles", true, "burst collected goals into colored particles")
+	flag.IntVar(&config.CelebrateEvery, "celebrate-every", 1, "celebrate every this many goals collected, 0 for only at the end of a round")
+	flag.StringVar(&config.AssetDir, "assets", ".", "directory with the assets and their assets.json manifest")
+	flag.StringVar(&config.Background, "background", "", "picture (PNG or BMP) to draw behind the game, by default the \"background\" asset if there is one")
+	flag.Float64Var(&config.Squash, "squash", 0, "stretch the markers along their movement by up to this fraction at full speed")
+	flag.StringVar(&config.Filter, "filter", FILTER_NORMAL, "post processing for low vision: normal, contrast or invert")
+	flag.DurationVar(&config.IdleTimeout, "idle", 0, "pause the game after this long without any input and start a fresh round on the next input")
+	flag.IntVar(&config.ShadowOffset, "shadow", 0, "draw goals with a drop shadow offset by this many pixels")
+	shadowColor := flag.String("shadow-color", "000000", "color of the goal drop shadow as RRGGBB")
+	flag.BoolVar(&config.TestMode, "test", false, "start in test mode with all assists off (F8 switches between practice and test)")
+	flag.BoolVar(&config.PitchByOrder, "pitch", false, "raise the pitch of the collection sound as the goals are collected")
+	flag.Int64Var(&config.Seed, "seed", 0, "seed for the random goal placement, 0 picks one from the clock")
+	flag.StringVar(&config.Reveal, "reveal", REVEAL_NONE, "how new goals appear: none, fade, scale or drop")
+	flag.DurationVar(&config.RevealTime, "reveal-time", time.Second/2, "how long the goal reveal animation lasts")
+	flag.BoolVar(&config.RevealCollide, "reveal-collide", false, "goals can be collected while still being revealed")
+	flag.StringVar(&config.ControlPath, "control", "", "accept control commands on this unix socket")
+	flag.StringVar(&config.Mode, "mode", MODE_ALPHABET, "game mode: alphabet, ordered, whack, free, maze, race, paint, simon or track")
+	flag.StringVar(&config.GoalSet, "goals", GOALS_LETTERS, "goals to collect: letters, numbers (1 to 20) or shapes")
+	flag.BoolVar(&config.FinishScreen, "finish-screen", true, "after the last goal celebrate and show the time until a button is pressed (not with -end stop)")
+	flag.StringVar(&config.EndPolicy, "end", END_LOOP, "after the last goal: loop, stop (show a win screen) or next (new round)")
+	flag.StringVar(&config.Camera, "camera", CAMERA_OFF, "scroll the view to follow a player: off, player or centroid")
+	flag.IntVar(&config.World, "world", 1, "make the playfield this many screens wide and high, at most 3, the view follows player 1 if -camera is off")
+	flag.BoolVar(&config.Minimap, "minimap", true, "show a map of the goals and players when -world is more than 1")
+	flag.BoolVar(&config.Hint, "hint", false, "show an arrow by each marker pointing the way to the current goal when it is far away")
+	flag.Float64Var(&config.HintDistance, "hint-distance", 300, "how far (in pixels) the goal has to be for -hint to show the arrow")
+	flag.StringVar(&config.LayoutPath, "layout", "", "load the goals and their positions from this layout file")
+	flag.StringVar(&config.MazePath, "maze", "", "maze file to play in maze mode")
+	flag.StringVar(&config.LayoutOut, "layout-out", "layout.json", "file F2 saves the current goal layout to")
+	flag.IntVar(&config.TractorButton, "tractor", -1, "joystick button that pulls the marker towards a nearby goal while held, -1 to disable")
+	flag.Float64Var(&config.TractorRange, "tractor-range", 200, "how close (in pixels) the marker must be to the goal for the tractor to work")
+	flag.Float64Var(&config.TractorStrength, "tractor-strength", 6, "how far (in pixels) the tractor pulls the marker each frame")
+	flag.Float64Var(&config.AvoidDistance, "avoid-dist", 0, "place goals at least this many pixels from recent goal positions")
+	flag.IntVar(&config.AvoidHistory, "avoid-history", 1, "how many recent goal positions new goals keep away from")
+	flag.StringVar(&config.Difficulty, "difficulty", DIFFICULTY_NORMAL, "easy, normal or hard, sets -goal-size, -speed, -goal-count and -goal-speed unless they are given")
+	flag.IntVar(&config.GoalSize, "goal-size", 60, "font size the goals are drawn at")
+	flag.Float64Var(&config.Speed, "speed", STEP, "how far (in pixels) a marker moves each update at full stick")
+	flag.IntVar(&config.GoalCount, "goal-count", 0, "only use this many of the goals, 0 for all of them")
+	flag.BoolVar(&config.Adapt, "adapt", false, "make the goals smaller and further away as the players get quicker")
+	flag.DurationVar(&config.AdaptFast, "adapt-fast", 3*time.Second, "collecting a goal quicker than this makes the game harder")
+	flag.DurationVar(&config.AdaptSlow, "adapt-slow", 10*time.Second, "collecting a goal slower than this makes the game easier")
+	flag.Float64Var(&config.AdaptStep, "adapt-step", 0.1, "how much each quick or slow goal changes the level, which goes from 0 to 1")
+	flag.Float64Var(&config.AdaptMinScale, "adapt-min-scale", 0.5, "how small the goals get at the top level, as a fraction of their size")
+	flag.Float64Var(&config.AdaptDistance, "adapt-distance", 500, "how far (in pixels) from the markers goals appear at the top level")
+	flag.BoolVar(&config.Inertia, "inertia", false, "the stick speeds the marker up and it keeps going, instead of the stick setting its speed")
+	flag.Float64Var(&config.Acceleration, "acceleration", 0.1, "with -inertia, how much of the stick position is added to the speed each update")
+	flag.Float64Var(&config.Friction, "friction", 0.1, "with -inertia, the fraction of the speed lost each update")
+	flag.IntVar(&config.ScreenshotButton, "screenshot-button", -1, "button that saves a screenshot (print screen always does), -1 for none")
+	flag.StringVar(&config.ScreenshotDir, "screenshots", "screenshots", "directory screenshots are saved in")
+	flag.IntVar(&config.ClearButton, "clear-button", 1, "button that clears the picture in paint mode")
+	flag.IntVar(&config.ColorButton, "color-button", 2, "button that changes the paint color in paint mode")
+	flag.IntVar(&config.CollectButton, "collect-button", -1, "players have to press this button while on a goal to collect it, -1 to collect by touching")
+	flag.IntVar(&config.Dwell, "dwell", 1, "how many updates in a row a marker has to stay on a goal to collect it (see -update-rate)")
+	flag.DurationVar(&config.TrackTime, "track-time", time.Minute, "how long the tracking exercise lasts, 0 for as long as you like")
+	flag.Float64Var(&config.TrackSpeed, "track-speed", 0.5, "how fast the dot moves in the tracking exercise, 1 is quite hard")
+	flag.StringVar(&config.Edges, "edges", EDGE_WRAP, "what markers do at the screen edge: wrap (to the other side), wall (stop) or bounce")
+	flag.BoolVar(&config.Coop, "coop", false, "co-op play: a goal is only collected when all the players are on it at once")
+	flag.Float64Var(&config.GoalSpeed, "goal-speed", 0, "make the goals drift around the screen at this many pixels a second")
+	flag.IntVar(&config.Obstacles, "obstacles", 0, "put this many obstacles for the players to avoid on the screen")
+	flag.StringVar(&config.ObstaclePenalty, "obstacle-penalty", OBSTACLE_POINTS, "what touching an obstacle does: points (lose points) or reset (back to the middle)")
+	flag.IntVar(&config.Preview, "preview", 3, "how many upcoming goals are shown in ordered mode")
+	flag.Float64Var(&config.PushStrength, "push", 0, "push a marker this many pixels away from a wrong goal in ordered mode")
+	flag.IntVar(&config.GridCols, "grid-cols", 4, "columns in the whack-a-mole grid")
+	flag.IntVar(&config.GridRows, "grid-rows", 3, "rows in the whack-a-mole grid")
+	flag.DurationVar(&config.SpawnInterval, "spawn", time.Second/2, "pause before the next whack-a-mole goal appears")
+	flag.DurationVar(&config.GoalTimeout, "timeout", 3*time.Second, "how long a whack-a-mole goal waits before moving")
+	flag.BoolVar(&config.MissPenalty, "miss-penalty", false, "lose a point when a whack-a-mole goal times out")
+	bindings := flag.String("bind", "", "route joystick input to players, e.g. 1.buttons=0 sends joystick 1's buttons to player 0")
+	flag.IntVar(&config.KeyboardPlayer, "keyboard", -1, "player the arrow keys (or WASD) and space control, -1 for only when there are no joysticks")
+	flag.IntVar(&config.MousePlayer, "mouse", -1, "player that follows the mouse pointer (or trackball), -1 for none")
+	flag.IntVar(&config.Deadzones.Default, "deadzone", 2000, "how far (out of 32767) a stick axis must move before it counts")
+	deadzones := flag.String("deadzones", "", "deadzones for particular sticks or axes, e.g. 1=4000,0.1=1500 (device 0's axis 1)")
+	deadzoneFile := flag.String("deadzone-file", "", "JSON file mapping \"dev\" or \"dev.axis\" to deadzones, -deadzones wins over it")
+	flag.StringVar(&config.CalibrationPath, "calibration", "calibration.json", "file the stick calibrations (F4) are kept in")
+	flag.StringVar(&config.ControllerDB, "controllerdb", "", "gamecontrollerdb.txt file mapping sticks (by name) to standard controls")
+	flag.StringVar(&config.ButtonsPath, "buttons", "buttons.json", "file the button actions set on the remap screen (F9) are kept in")
+	axisRoles := flag.String("axis-roles", "", "what raw axes are for on sticks not in -controllerdb, e.g. 2=lefttrigger,5=righttrigger")
+	flag.Float64Var(&config.TriggerBoost, "trigger-boost", 2, "how many times faster a marker goes with a trigger squeezed all the way")
+	flag.BoolVar(&config.TwoHands, "two-hands", false, "give each player a second marker driven by the right stick (axes 2 and 3)")
+	flag.BoolVar(&config.ChooseSticks, "choose", false, "start with a screen for picking which joysticks play")
+	flag.StringVar(&config.RecordPath, "record", "", "record the input of every simulation step to this file")
+	flag.StringVar(&config.VideoPath, "video", "", "record the screen to this .gif, or to a directory of numbered PNGs for anything else")
+	flag.IntVar(&config.VideoRate, "video-fps", 10, "most frames a second -video records")
+	flag.IntVar(&config.VideoShrink, "video-shrink", 2, "how many times smaller than the screen -video frames are")
+	flag.StringVar(&config.ReplayPath, "replay", "", "play back a recording instead of using the joysticks, then quit")
+	flag.StringVar(&config.FakeInput, "fake-input", "", "play a script of made up joystick events, for trying things without joysticks")
+	flag.BoolVar(&config.Rumble, "rumble", true, "briefly shake the controller that collected a goal (with -evdev only)")
+	flag.StringVar(&config.Evdev, "evdev", "", "read joysticks from comma separated /dev/input/event* devices, or auto, instead of through SDL (Linux only)")
+	flag.IntVar(&config.TouchPlayer, "touch", -1, "player that jumps to a touch and follows the finger while it is dragged, -1 for none")
+	invert := flag.String("invert", "", "stick axes that point the wrong way, as dev.axis separated by commas")
+	flag.BoolVar(&config.FlipY, "flip-y", false, "turn up and down around on every stick (F10 switches it)")
+	flag.StringVar(&config.Curve, "curve", CURVE_LINEAR, "how stick position turns into speed: linear, power, cubic or exp")
+	flag.Float64Var(&config.CurveExponent, "curve-exponent", 2, "exponent of the power curve, or how sharply the exp curve rises")
+	flag.StringVar(&config.ProfilesPath, "profiles", "", "JSON file of profiles (deadzone, invert, curve, buttons, color) by joystick name")
+	flag.StringVar(&config.PhoneAddr, "phone", "", "serve a gamepad page for phones and tablets on this address, e.g. :8080")
+	flag.IntVar(&config.PhonePlayer, "phone-player", 0, "player a phone controls, a page opened with ?player=N controls player N")
+	flag.StringVar(&config.MidiPath, "midi", "", "raw MIDI device to use as a controller, e.g. /dev/snd/midiC1D0")
+	flag.IntVar(&config.MidiPlayer, "midi-player", 0, "player the MIDI controller moves")
+	midiCC := flag.String("midi-cc", "1=0,2=1", "which MIDI controllers move which axes, as cc=axis")
+	flag.IntVar(&config.TiltDevice, "tilt", -1, "joystick (counted from 0) that is a controller's motion sensors, to steer by tilting, -1 for none")
+	flag.Float64Var(&config.TiltSensitivity, "tilt-sensitivity", 4, "how strongly tilting the controller moves the marker")
+	flag.IntVar(&config.TiltRecenter, "tilt-recenter", -1, "button of the -tilt device that makes the way the controller is held count as level, -1 for none")
+	flag.IntVar(&config.TiltPlayer, "tilt-player", 0, "player the -tilt device steers")
+	flag.Float64Var(&config.Smoothing, "smooth", 0, "smooth stick movement, from 0 (off) towards 1 (very smooth)")
+	flag.DurationVar(&config.DriftTime, "drift", 0, "take a stick resting slightly off center this long, with no buttons pressed, as drift and correct for it, 0 to never")
+	flag.StringVar(&config.DriftPath, "drift-file", "drift.json", "file the learned stick drift is kept in")
+	zones := flag.String("zones", "", "keep players in areas of the screen, e.g. 0=0,0,512,768;1=512,0,512,768")
+	flag.BoolVar(&config.Split, "split", false, "keep each player in their own vertical strip of the screen")
+	words := flag.String("words", "", "comma separated list of words to spell instead of the alphabet")
+	wordsFile := flag.String("words-file", "", "text file of words to spell, one per line, before any given with -words")
+	flag.Parse()
+
+	var err error
+	for _, c := range []struct {
+		name, value string
+		choices     []string
+	}{
+		{"mode", config.Mode, []string{MODE_ALPHABET, MODE_ORDERED, MODE_WHACK, MODE_FREE, MODE_MAZE, MODE_RACE, MODE_PAINT, MODE_SIMON, MODE_TRACK}},
+		{"goals", config.GoalSet, []string{GOALS_LETTERS, GOALS_NUMBERS, GOALS_SHAPES}},
+		{"end", config.EndPolicy, []string{END_LOOP, END_STOP, END_NEXT}},
+		{"camera", config.Camera, []string{CAMERA_OFF, CAMERA_PLAYER, CAMERA_CENTROID}},
+		{"edges", config.Edges, []string{EDGE_WRAP, EDGE_WALL, EDGE_BOUNCE}},
+		{"obstacle-penalty", config.ObstaclePenalty, []string{OBSTACLE_POINTS, OBSTACLE_RESET}},
+		{"filter", config.Filter, []string{FILTER_NORMAL, FILTER_CONTRAST, FILTER_INVERT}},
+		{"reveal", config.Reveal, []string{REVEAL_NONE, REVEAL_FADE, REVEAL_SCALE, REVEAL_DROP}},
+		{"curve", config.Curve, []string{CURVE_LINEAR, CURVE_POWER, CURVE_CUBIC, CURVE_EXP}},
+	} {
+		if err = checkChoice(c.name, c.value, c.choices); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	if err = applyDifficulty(config.Difficulty); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.ShadowColor, err = parseColor(*shadowColor); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.Inputs, err = parseInputMap(*bindings); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if *deadzoneFile != "" {
+		if err = config.Deadzones.load(*deadzoneFile); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	if err = config.Deadzones.parse(*deadzones); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.AxisRoles, err = parseAxisRoles(*axisRoles); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.Invert, err = parseInversions(*invert); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.MidiCC, err = parseMidiCC(*midiCC); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if config.World < 1 {
+		config.World = 1
+	} else if config.World > WORLD_MAX {
+		config.World = WORLD_MAX
+	}
+	if config.World > 1 && config.Camera == CAMERA_OFF {
+		// the rest of the playfield could never be seen
+		config.Camera = CAMERA_PLAYER
+	}
+	if config.Zones, err = parseZones(*zones); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if (config.RecordPath != "" || config.ReplayPath != "") && config.ControlPath != "" {
+		// control commands change the game outside the simulation steps, so they would not replay
+		fmt.Println("-control cannot be used with -record or -replay")
+		os.Exit(2)
+	}
+	if config.RecordPath != "" || config.ReplayPath != "" {
+		// neither would players joining or leaving part way through
+		config.Hotplug = 0
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	if config.Friction <= 0 || config.Friction > 1 {
+		fmt.Println("-friction must be more than 0 and at most 1")
+		os.Exit(2)
+	}
+	config.BackgroundColor = BACKGROUND
+	config.GoalColor = sdl.Color{255, 255, 255, 0}
+	config.TextColor = sdl.Color{255, 255, 255, 0}
+	if *themePath != "" {
+		if err = loadTheme(*themePath); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	if *font != "" {
+		config.Font = *font
+	}
+	if _, ok := palettes[config.Palette]; !ok {
+		fmt.Printf("unknown palette %q, expected classic, okabe-ito, tol or contrast\n", config.Palette)
+		os.Exit(2)
+	}
+	if config.Mode == MODE_MAZE && config.MazePath == "" {
+		fmt.Println("-mode maze needs a -maze file")
+		os.Exit(2)
+	}
+	if config.Smoothing < 0 || config.Smoothing >= 1 {
+		fmt.Println("-smooth must be at least 0 and less than 1")
+		os.Exit(2)
+	}
+	if config.Trail > TRAIL_MAX {
+		config.Trail = TRAIL_MAX
+	}
+	if config.UpdateRate < 1 {
+		config.UpdateRate = 1
+	}
+	if config.FrameRate < 1 {
+		config.FrameRate = 1
+	}
+	// GIF frame times are in hundredths of a second
+	if config.VideoRate < 1 {
+		config.VideoRate = 1
+	} else if config.VideoRate > 100 {
+		config.VideoRate = 100
+	}
+	if config.VideoShrink < 1 {
+		config.VideoShrink = 1
+	}
+
+	if *wordsFile != "" {
+		if config.Words, err = loadWords(*wordsFile); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+	for _, s := range strings.Split(*sprites, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			config.Sprites = append(config.Sprites, s)
+		}
+	}
+	for _, w := range strings.Split(*words, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			config.Words = append(config.Words, w)
+		}
+	}
+}
+
+// parseColor reads a color written as RRGGBB hex digits (a leading # is allowed)
+func parseColor(s string) (sdl.Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return sdl.Color{}, fmt.Errorf("bad color %q, expected RRGGBB", s)
+	}
+	return sdl.Color{uint8(v >> 16), uint8(v >> 8), uint8(v), 0}, nil
+}
+
+// checkChoice makes sure the value given for the flag name is one of choices
+func checkChoice(name, value string, choices []string) error {
+	for _, c := range choices {
+		if value == c {
+			return nil
+		}
+	}
+	last := len(choices) - 1
+	return fmt.Errorf("unknown -%s %q, expected %s or %s", name, value, strings.Join(choices[:last], ", "), choices[last])
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// useConfig sets config to c for the rest of the test, putting the old one back afterwards
+func useConfig(t *testing.T, c Config) {
+	old := config
+	config = c
+	t.Cleanup(func() { config = old })
+}
+
+func TestCheckChoice(t *testing.T) {
+	edges := []string{EDGE_WRAP, EDGE_WALL, EDGE_BOUNCE}
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"wrap", ""},
+		{"bounce", ""},
+		{"Bounce", `unknown -edges "Bounce", expected wrap, wall or bounce`},
+		{"", `unknown -edges "", expected wrap, wall or bounce`},
+	}
+	for _, tt := range tests {
+		err := checkChoice("edges", tt.value, edges)
+		if got := fmt.Sprint(err); tt.want == "" && err != nil || tt.want != "" && got != tt.want {
+			t.Errorf("checkChoice(%q) = %v, want %q", tt.value, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// The high contrast theme, for players who cannot see well.  F1 switches it on and off.
+const (
+	CONTRAST_BACKGROUND = uint32(0x00000000) // plain black behind everything
+	CONTRAST_GOAL       = uint32(0x00ffff00) // goals are bright yellow
+	CONTRAST_BORDER     = uint32(0x00ffffff) // markers get a white border
+	CONTRAST_OUTLINE    = 4                  // thickness of the black outline around goals
+	CONTRAST_BORDER_W   = 3                  // thickness of the border around markers
+)
+
+// drawContrast draws the goal in the high contrast theme, yellow with a thick black outline.  It is
+// made from Surface when first needed, and again whenever Surface changes.
+func (g *Goal) drawContrast(screen *sdl.Surface) {
+	if g.contrastOf != g.Surface {
+		if g.contrast != nil {
+			g.contrast.Free()
+		}
+		g.contrast = contrastSurface(g.Surface, CONTRAST_OUTLINE)
+		g.contrastOf = g.Surface
+	}
+	if g.contrast == nil {
+		screen.Blit(g.Rect(), g.Surface, nil)
+		return
+	}
+	r := g.Rect()
+	screen.Blit(&sdl.Rect{r.X - CONTRAST_OUTLINE, r.Y - CONTRAST_OUTLINE, 0, 0}, g.contrast, nil)
+}
+
+// contrastSurface gives a copy of the 32 bit alpha surface s, width pixels bigger on each side, with
+// everything drawn on s turned yellow and everything within width of it black
+func contrastSurface(s *sdl.Surface, width int) *sdl.Surface {
+	w, h := int(s.W), int(s.H)
+	out := newLike(s, w+2*width, h+2*width)
+	if out == nil {
+		return nil
+	}
+	f := s.Format
+	// only fairly solid pixels count as part of the goal, the edges of smoothed text are faint
+	solid := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < w && y < h && channel(pixelAt(s, x, y), f.Amask, f.Ashift) > 0x40
+	}
+	s.Lock()
+	out.Lock()
+	for y := 0; y < h+2*width; y++ {
+		for x := 0; x < w+2*width; x++ {
+			var p uint32
+			if solid(x-width, y-width) {
+				p = CONTRAST_GOAL | f.Amask
+			} else if nearSolid(solid, x-width, y-width, width) {
+				p = CONTRAST_BACKGROUND | f.Amask
+			}
+			*pixelPtr(out, x, y) = p
+		}
+	}
+	out.Unlock()
+	s.Unlock()
+	return out
+}
+
+// nearSolid reports whether any solid pixel is within a circle of radius r around x, y
+func nearSolid(solid func(x, y int) bool, x, y, r int) bool {
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy <= r*r && solid(x+dx, y+dy) {
+				return true
+			}
+		}
+	}
+	return false
+}
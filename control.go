@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// A ControlRequest is a command received on the control socket, waiting to be run by the main loop
+type ControlRequest struct {
+	Name  string
+	Args  []string
+	Reply chan string // gets "ok" or "error ..." once the command has run
+}
+
+// Control lets other programs drive a session through a local (unix) socket.  Each line sent is a
+// command; each command is answered with a line "ok" or "error <reason>".  Game events are sent to
+// every connection as lines starting with "event".
+type Control struct {
+	Requests chan ControlRequest
+
+	listener net.Listener
+	mu       sync.Mutex
+	conns    map[net.Conn]chan string // the lines waiting to be written to each connection
+}
+
+// how many lines can wait for a connection before events to it are dropped
+const CONTROL_BACKLOG = 64
+
+// ListenControl starts listening for control connections on the unix socket at path
+func ListenControl(path string) (*Control, error) {
+	// a socket left behind by an earlier run would stop us listening
+	removeSocket(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Control{Requests: make(chan ControlRequest), listener: l, conns: make(map[net.Conn]chan string)}
+	go c.accept()
+	return c, nil
+}
+
+// removeSocket removes the unix socket at path.  Anything else there is left alone, so a mistyped
+// path cannot delete a file.
+func removeSocket(path string) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+}
+
+func (c *Control) accept() {
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			return
+		}
+		out := make(chan string, CONTROL_BACKLOG)
+		c.mu.Lock()
+		c.conns[conn] = out
+		c.mu.Unlock()
+		go c.write(conn, out)
+		go c.serve(conn, out)
+	}
+}
+
+// write sends the lines for one connection, so a client that stops reading only holds up itself
+func (c *Control) write(conn net.Conn, out chan string) {
+	for line := range out {
+		if _, err := fmt.Fprintln(conn, line); err != nil {
+			// the reading side notices the connection is gone and cleans up
+			conn.Close()
+		}
+	}
+}
+
+// serve reads commands from one connection and hands them to the main loop
+func (c *Control) serve(conn net.Conn, out chan string) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.conns, conn)
+		close(out)
+		c.mu.Unlock()
+		conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		req, ok := parseCommand(scanner.Text())
+		if !ok {
+			continue
+		}
+		c.Requests <- req
+		out <- <-req.Reply
+	}
+}
+
+// Broadcast sends a game event to every connection.  It never waits, a connection that has fallen
+// too far behind misses the event.
+func (c *Control) Broadcast(event string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, out := range c.conns {
+		select {
+		case out <- "event " + event:
+		default:
+		}
+	}
+}
+
+// Close stops listening and drops all the connections
+func (c *Control) Close() {
+	if c == nil {
+		return
+	}
+	addr := c.listener.Addr().String()
+	c.listener.Close()
+	c.mu.Lock()
+	for conn := range c.conns {
+		conn.Close()
+	}
+	c.mu.Unlock()
+	removeSocket(addr)
+}
+
+// parseCommand splits a line into a command and its arguments.  Blank lines are not commands.
+func parseCommand(line string) (ControlRequest, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ControlRequest{}, false
+	}
+	return ControlRequest{Name: strings.ToLower(fields[0]), Args: fields[1:], Reply: make(chan string, 1)}, true
+}
+
+// RunCommand runs a control command against the game.  It returns the reply and whether the session
+// should keep running.
+func (g *Game) RunCommand(name string, args []string) (string, bool) {
+	switch name {
+	case "start", "restart":
+		g.Restart()
+	case "advance":
+		g.Override(TEACHER_ADVANCE)
+	case "celebrate":
+		g.Override(TEACHER_CELEBRATE)
+	case "set":
+		if len(args) != 2 || args[0] != "difficulty" {
+			return "error expected set difficulty <easy, normal or hard>", true
+		}
+		if err := setDifficulty(args[1]); err != nil {
+			return "error " + err.Error(), true
+		}
+	case "quit":
+		return "ok", false
+	default:
+		return "error unknown command " + name, true
+	}
+	return "ok", true
+}
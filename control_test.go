@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		name string
+		args []string
+		ok   bool
+	}{
+		{"", "", nil, false},
+		{"   ", "", nil, false},
+		{"Advance", "advance", []string{}, true},
+		{" set  difficulty hard ", "set", []string{"difficulty", "hard"}, true},
+	}
+	for _, tt := range tests {
+		req, ok := parseCommand(tt.line)
+		if ok != tt.ok || req.Name != tt.name || ok && !reflect.DeepEqual(req.Args, tt.args) {
+			t.Errorf("parseCommand(%q) = %q %q %v, want %q %q %v", tt.line, req.Name, req.Args, ok, tt.name, tt.args, tt.ok)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	tests := []struct {
+		line    string
+		reply   string
+		running bool
+		goal    int
+		speed   float64
+	}{
+		{"advance", "ok", true, 1, STEP},
+		{"start", "ok", true, 0, STEP},
+		{"set difficulty hard", "ok", true, 0, 20},
+		{"set difficulty silly", `error unknown difficulty "silly", expected easy, normal or hard`, true, 0, STEP},
+		{"set speed 3", "error expected set difficulty <easy, normal or hard>", true, 0, STEP},
+		{"dance", "error unknown command dance", true, 0, STEP},
+		{"quit", "ok", false, 0, STEP},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{Speed: STEP, CollectButton: -1})
+		g := NewGame([]Marker{{}}, letterGoals("ABC", nil))
+		req, _ := parseCommand(tt.line)
+		reply, running := g.RunCommand(req.Name, req.Args)
+		if reply != tt.reply || running != tt.running || g.CurGoal != tt.goal || config.Speed != tt.speed {
+			t.Errorf("%q gave %q %v at goal %d speed %v, want %q %v %d %v", tt.line, reply, running, g.CurGoal, config.Speed, tt.reply, tt.running, tt.goal, tt.speed)
+		}
+	}
+}
+
+func TestControlSocket(t *testing.T) {
+	useConfig(t, Config{CollectButton: -1})
+	path := filepath.Join(t.TempDir(), "control")
+	c, err := ListenControl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	g := NewGame([]Marker{{}}, letterGoals("ABC", nil))
+	go func() {
+		for req := range c.Requests {
+			reply, _ := g.RunCommand(req.Name, req.Args)
+			req.Reply <- reply
+		}
+	}()
+
+	// a client that never reads must not hold up the game
+	idle, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idle.Close()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	lines := bufio.NewScanner(conn)
+	for _, tt := range []struct{ send, want string }{{"advance", "ok"}, {"bogus", "error unknown command bogus"}} {
+		conn.Write([]byte(tt.send + "\n"))
+		if !lines.Scan() || lines.Text() != tt.want {
+			t.Fatalf("%q got %q, want %q", tt.send, lines.Text(), tt.want)
+		}
+	}
+	if g.CurGoal != 1 {
+		t.Errorf("advance over the socket left the game at goal %d", g.CurGoal)
+	}
+	for i := 0; i < 100*CONTROL_BACKLOG; i++ {
+		c.Broadcast("collect A")
+	}
+	if !lines.Scan() || lines.Text() != "event collect A" {
+		t.Errorf("got %q, want an event", lines.Text())
+	}
+}
+
+func TestRemoveSocketLeavesFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ListenControl(path); err == nil {
+		t.Errorf("listening over a file worked")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the file was removed: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Logical axes, in SDL's game controller order.  Sticks without a mapping use their raw axis numbers,
+// so their first two axes are the left stick.
+const (
+	AXIS_LEFTX = iota
+	AXIS_LEFTY
+	AXIS_RIGHTX
+	AXIS_RIGHTY
+	AXIS_TRIGGERLEFT
+	AXIS_TRIGGERRIGHT
+)
+
+// Logical buttons, in SDL's game controller order
+const (
+	BUTTON_A = iota
+	BUTTON_B
+	BUTTON_X
+	BUTTON_Y
+	BUTTON_BACK
+	BUTTON_GUIDE
+	BUTTON_START
+	BUTTON_LEFTSTICK
+	BUTTON_RIGHTSTICK
+	BUTTON_LEFTSHOULDER
+	BUTTON_RIGHTSHOULDER
+	BUTTON_DPAD_UP
+	BUTTON_DPAD_DOWN
+	BUTTON_DPAD_LEFT
+	BUTTON_DPAD_RIGHT
+)
+
+// the names gamecontrollerdb.txt uses for the logical axes and buttons
+var (
+	axisNames = map[string]int{
+		"leftx": AXIS_LEFTX, "lefty": AXIS_LEFTY, "rightx": AXIS_RIGHTX, "righty": AXIS_RIGHTY,
+		"lefttrigger": AXIS_TRIGGERLEFT, "righttrigger": AXIS_TRIGGERRIGHT,
+	}
+	buttonNames = map[string]int{
+		"a": BUTTON_A, "b": BUTTON_B, "x": BUTTON_X, "y": BUTTON_Y,
+		"back": BUTTON_BACK, "guide": BUTTON_GUIDE, "start": BUTTON_START,
+		"leftstick": BUTTON_LEFTSTICK, "rightstick": BUTTON_RIGHTSTICK,
+		"leftshoulder": BUTTON_LEFTSHOULDER, "rightshoulder": BUTTON_RIGHTSHOULDER,
+		"dpup": BUTTON_DPAD_UP, "dpdown": BUTTON_DPAD_DOWN, "dpleft": BUTTON_DPAD_LEFT, "dpright": BUTTON_DPAD_RIGHT,
+	}
+)
+
+// A ControllerMap turns a stick's raw axis and button numbers into logical ones
+type ControllerMap struct {
+	Axes    map[int]int  // raw axis to logical axis
+	Invert  map[int]bool // raw axes that are upside down
+	Buttons map[int]int  // raw button to logical button
+}
+
+// Axis gives the logical axis and value for a raw axis
+func (cm *ControllerMap) Axis(axis int, v int16) (int, int16, bool) {
+	logical, ok := cm.Axes[axis]
+	if ok && cm.Invert[axis] {
+		v = invertAxis(v)
+	}
+	return logical, v, ok
+}
+
+// Button gives the logical button for a raw button
+func (cm *ControllerMap) Button(button int) (int, bool) {
+	logical, ok := cm.Buttons[button]
+	return logical, ok
+}
+
+// parseAxisRoles reads what raw axes are for on sticks without a mapping, as "axis=role" separated by
+// commas, where role is a gamecontrollerdb.txt name such as lefttrigger
+func parseAxisRoles(s string) (map[int]int, error) {
+	roles := make(map[int]int)
+	for _, r := range strings.Split(s, ",") {
+		if r = strings.TrimSpace(r); r == "" {
+			continue
+		}
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("bad axis role %q, expected axis=role", r)
+		}
+		axis, err := strconv.Atoi(parts[0])
+		if err != nil || axis < 0 {
+			return nil, fmt.Errorf("bad axis in axis role %q", r)
+		}
+		role, ok := axisNames[strings.TrimSpace(parts[1])]
+		if !ok {
+			return nil, fmt.Errorf("unknown role in axis role %q", r)
+		}
+		roles[axis] = role
+	}
+	return roles, nil
+}
+
+// loadControllerDB reads an SDL game controller database (gamecontrollerdb.txt).  SDL 1.2 does not give
+// joysticks a GUID, so the mappings are found by the name of the stick instead.  Only the axes and
+// buttons that map straight onto a logical axis or button are used, hats are handled as they always
+// were and half axes are skipped.
+func loadControllerDB(path string) (map[string]*ControllerMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	maps := make(map[string]*ControllerMap)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		name := fields[1]
+		if _, ok := maps[name]; ok {
+			// the first mapping for a name wins, like SDL
+			continue
+		}
+		maps[name] = parseMapping(fields[2:])
+	}
+	return maps, scanner.Err()
+}
+
+// parseMapping reads the "logical:raw" fields of a mapping line
+func parseMapping(fields []string) *ControllerMap {
+	cm := &ControllerMap{Axes: make(map[int]int), Invert: make(map[int]bool), Buttons: make(map[int]int)}
+	for _, f := range fields {
+		parts := strings.SplitN(f, ":", 2)
+		if len(parts) != 2 || len(parts[1]) < 2 {
+			continue
+		}
+		target, src := parts[0], parts[1]
+		invert := strings.HasSuffix(src, "~")
+		src = strings.TrimSuffix(src, "~")
+		n, err := strconv.Atoi(src[1:])
+		if err != nil {
+			continue
+		}
+		if axis, ok := axisNames[target]; ok && src[0] == 'a' {
+			cm.Axes[n] = axis
+			cm.Invert[n] = invert
+		} else if button, ok := buttonNames[target]; ok && src[0] == 'b' {
+			cm.Buttons[n] = button
+		}
+	}
+	return cm
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"math"
+)
+
+// Response curves, how stick position turns into speed
+const (
+	CURVE_LINEAR = "linear" // speed is the stick position
+	CURVE_POWER  = "power"  // speed is the position raised to CurveExponent
+	CURVE_CUBIC  = "cubic"  // speed is the position cubed
+	CURVE_EXP    = "exp"    // speed grows exponentially with the position, CurveExponent sets how sharply
+)
+
+// applyCurve bends an axis value with a response curve.  The curves keep the ends where they are, so a
+// stick pushed all the way still goes full speed, but give finer control near the center.
+func applyCurve(v int16, curve string, exponent float64) int16 {
+	n := float64(v) / 32768
+	a := math.Abs(n)
+	switch curve {
+	case CURVE_POWER:
+		a = math.Pow(a, exponent)
+	case CURVE_CUBIC:
+		a = a * a * a
+	case CURVE_EXP:
+		if exponent > 0 {
+			a = (math.Exp(exponent*a) - 1) / (math.Exp(exponent) - 1)
+		}
+	default:
+		return v
+	}
+	return int16(math.Copysign(a, n) * 32767)
+}
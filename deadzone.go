@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Deadzones holds how far each stick axis has to move before it counts.  A setting for a device's axis
+// beats one for the whole device, which beats the default.
+type Deadzones struct {
+	Default int
+	dev     map[int]int
+	axis    map[[2]int]int
+}
+
+// For gives the deadzone of an axis on a device
+func (d Deadzones) For(dev, axis int) int {
+	if v, ok := d.axis[[2]int{dev, axis}]; ok {
+		return v
+	}
+	if v, ok := d.dev[dev]; ok {
+		return v
+	}
+	return d.Default
+}
+
+// Apply gives the axis value with its deadzone taken into account, 0 inside the deadzone
+func (d Deadzones) Apply(dev, axis int, value int16) int16 {
+	dz := d.For(dev, axis)
+	if int(value) > dz || int(value) < -dz {
+		return value
+	}
+	return 0
+}
+
+// set a deadzone, key is "dev" or "dev.axis"
+func (d *Deadzones) set(key string, v int) error {
+	if d.dev == nil {
+		d.dev = make(map[int]int)
+		d.axis = make(map[[2]int]int)
+	}
+	if v < 0 || v > 32767 {
+		return fmt.Errorf("bad deadzone %d for %q, expected 0 to 32767", v, key)
+	}
+	parts := strings.SplitN(key, ".", 2)
+	dev, err := strconv.Atoi(parts[0])
+	if err != nil || dev < 0 {
+		return fmt.Errorf("bad device in deadzone %q", key)
+	}
+	if len(parts) == 1 {
+		d.dev[dev] = v
+		return nil
+	}
+	axis, err := strconv.Atoi(parts[1])
+	if err != nil || axis < 0 {
+		return fmt.Errorf("bad axis in deadzone %q", key)
+	}
+	d.axis[[2]int{dev, axis}] = v
+	return nil
+}
+
+// parse reads deadzones of the form "dev=value" or "dev.axis=value", separated by commas
+func (d *Deadzones) parse(s string) error {
+	for _, z := range strings.Split(s, ",") {
+		if z = strings.TrimSpace(z); z == "" {
+			continue
+		}
+		parts := strings.SplitN(z, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("bad deadzone %q, expected dev=value or dev.axis=value", z)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return fmt.Errorf("bad value in deadzone %q", z)
+		}
+		if err = d.set(strings.TrimSpace(parts[0]), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load deadzones from a JSON file, an object mapping "dev" or "dev.axis" to the deadzone
+func (d *Deadzones) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var zones map[string]int
+	if err = json.Unmarshal(data, &zones); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	// go through them in order so the error reported is always the same
+	keys := make([]string, 0, len(zones))
+	for k := range zones {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err = d.set(k, zones[k]); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeadzones(t *testing.T) {
+	d := Deadzones{Default: 1000}
+	if err := d.parse("1=2000, 1.3=500, 2.0=0"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		dev, axis int
+		want      int
+	}{
+		{0, 0, 1000},
+		{1, 0, 2000},
+		{1, 3, 500},
+		{2, 0, 0},
+		{2, 1, 1000},
+	}
+	for _, tt := range tests {
+		if got := d.For(tt.dev, tt.axis); got != tt.want {
+			t.Errorf("For(%d, %d) = %d, want %d", tt.dev, tt.axis, got, tt.want)
+		}
+	}
+	if got := d.Apply(1, 0, -2000); got != 0 {
+		t.Errorf("Apply inside the deadzone gave %d", got)
+	}
+	if got := d.Apply(1, 0, -2001); got != -2001 {
+		t.Errorf("Apply outside the deadzone gave %d", got)
+	}
+}
+
+func TestDeadzonesParseErrors(t *testing.T) {
+	for _, s := range []string{"1", "x=100", "1.x=100", "-1=100", "1=big", "1=-1", "1=40000"} {
+		var d Deadzones
+		if err := d.parse(s); err == nil {
+			t.Errorf("parse(%q) did not fail", s)
+		}
+	}
+}
+
+func TestDeadzonesLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadzones.json")
+	if err := os.WriteFile(path, []byte(`{"0": 3000, "0.2": 100}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var d Deadzones
+	if err := d.load(path); err != nil {
+		t.Fatal(err)
+	}
+	if d.For(0, 1) != 3000 || d.For(0, 2) != 100 {
+		t.Errorf("loaded deadzones %d and %d, want 3000 and 100", d.For(0, 1), d.For(0, 2))
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// where the debug overlay goes, along the right of the screen
+	DEBUG_W     = 350
+	DEBUG_Y     = 40
+	DEBUG_LINE  = 22
+	DEBUG_COLOR = uint32(0x00000000)
+)
+
+// A DebugOverlay shows what the main loop is doing, for finding out why a controller feels laggy: the
+// frame rate, how many timer ticks were not drawn because every marker was still, the last raw value
+// of each joystick axis and how many of each kind of event came in.  F12 shows and hides it.
+type DebugOverlay struct {
+	Shown   bool
+	FPS     float64
+	Skipped int            // timer ticks skipped because nothing moved
+	Events  map[string]int // events by kind
+	Raw     map[int]map[int]int16
+
+	font   *ttf.Font
+	frames int       // frames drawn since since
+	since  time.Time // when the frame rate was last worked out
+	lines  []*Label
+}
+
+// Create a hidden debug overlay drawn with font
+func NewDebugOverlay(font *ttf.Font) *DebugOverlay {
+	return &DebugOverlay{Events: make(map[string]int), Raw: make(map[int]map[int]int16), font: font, since: time.Now()}
+}
+
+// Frame counts a frame drawn at now, working out the frame rate about once a second
+func (d *DebugOverlay) Frame(now time.Time) {
+	d.frames++
+	if elapsed := now.Sub(d.since); elapsed >= time.Second {
+		d.FPS = float64(d.frames) / elapsed.Seconds()
+		d.frames = 0
+		d.since = now
+	}
+}
+
+// Event counts an event by its kind
+func (d *DebugOverlay) Event(event interface{}) {
+	d.Events[strings.TrimPrefix(fmt.Sprintf("%T", event), "sdl.")]++
+}
+
+// Axis remembers the raw value of an axis of device dev, before anything is done to it
+func (d *DebugOverlay) Axis(dev, axis int, value int16) {
+	if d.Raw[dev] == nil {
+		d.Raw[dev] = make(map[int]int16)
+	}
+	d.Raw[dev][axis] = value
+}
+
+// text gives the overlay's lines
+func (d *DebugOverlay) text() []string {
+	lines := []string{
+		fmt.Sprintf("fps: %.1f", d.FPS),
+		fmt.Sprintf("skipped redraws: %d", d.Skipped),
+	}
+	var devs []int
+	for dev := range d.Raw {
+		devs = append(devs, dev)
+	}
+	sort.Ints(devs)
+	for _, dev := range devs {
+		var axes []int
+		for axis := range d.Raw[dev] {
+			axes = append(axes, axis)
+		}
+		sort.Ints(axes)
+		var parts []string
+		for _, axis := range axes {
+			parts = append(parts, fmt.Sprintf("%d:%d", axis, d.Raw[dev][axis]))
+		}
+		lines = append(lines, fmt.Sprintf("js%d %s", dev, strings.Join(parts, " ")))
+	}
+	var kinds []string
+	for kind := range d.Events {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		lines = append(lines, fmt.Sprintf("%s: %d", kind, d.Events[kind]))
+	}
+	return lines
+}
+
+// Update the overlay's labels
+func (d *DebugOverlay) Update() {
+	text := d.text()
+	for len(d.lines) < len(text) {
+		d.lines = append(d.lines, &Label{Font: d.font, Color: config.TextColor, X: frameW - DEBUG_W, Y: DEBUG_Y + 5 + len(d.lines)*DEBUG_LINE})
+	}
+	for i, l := range d.lines {
+		if i < len(text) {
+			l.SetText(text[i])
+		} else {
+			l.SetText("")
+		}
+	}
+}
+
+// Get the box the overlay covers
+func (d *DebugOverlay) Rect() *sdl.Rect {
+	return &sdl.Rect{int16(frameW - DEBUG_W - 10), DEBUG_Y, DEBUG_W, uint16(len(d.lines)*DEBUG_LINE + 10)}
+}
+
+// Draw the overlay
+func (d *DebugOverlay) Draw(screen *sdl.Surface) {
+	screen.FillRect(d.Rect(), DEBUG_COLOR)
+	for _, l := range d.lines {
+		l.Draw(screen)
+	}
+}
+
+// Free the overlay's text
+func (d *DebugOverlay) Free() {
+	for _, l := range d.lines {
+		l.Free()
+	}
+	d.lines = nil
+}
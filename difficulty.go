@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Difficulty presets
+const (
+	DIFFICULTY_EASY   = "easy"
+	DIFFICULTY_NORMAL = "normal"
+	DIFFICULTY_HARD   = "hard"
+)
+
+// A Difficulty is a preset of the settings that make the game easier or harder
+type Difficulty struct {
+	GoalSize  int     // font size the goals are drawn at
+	Speed     float64 // how far (in pixels) a marker moves in an update at full stick
+	GoalCount int     // how many goals there are, 0 for all of them
+	GoalSpeed float64 // how fast (in pixels a second) the goals drift, 0 for not at all
+}
+
+var difficulties = map[string]Difficulty{
+	DIFFICULTY_EASY:   {GoalSize: 90, Speed: 10, GoalCount: 10},
+	DIFFICULTY_NORMAL: {GoalSize: 60, Speed: STEP},
+	DIFFICULTY_HARD:   {GoalSize: 40, Speed: 20, GoalSpeed: 40},
+}
+
+// applyDifficulty fills in the settings from the named preset, leaving alone any that were given on
+// the command line
+func applyDifficulty(name string) error {
+	d, ok := difficulties[name]
+	if !ok {
+		return fmt.Errorf("unknown difficulty %q, expected easy, normal or hard", name)
+	}
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	if !set["goal-size"] {
+		config.GoalSize = d.GoalSize
+	}
+	if !set["speed"] {
+		config.Speed = d.Speed
+	}
+	if !set["goal-count"] {
+		config.GoalCount = d.GoalCount
+	}
+	if !set["goal-speed"] {
+		config.GoalSpeed = d.GoalSpeed
+	}
+	return nil
+}
+
+// setDifficulty switches to the named preset part way through a session.  The goals are already
+// made, so only how fast the markers and goals move changes.
+func setDifficulty(name string) error {
+	d, ok := difficulties[name]
+	if !ok {
+		return fmt.Errorf("unknown difficulty %q, expected easy, normal or hard", name)
+	}
+	config.Difficulty = name
+	config.Speed, config.GoalSpeed = d.Speed, d.GoalSpeed
+	return nil
+}
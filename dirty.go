@@ -0,0 +1,99 @@
+package main
+
+import (
+	"container/list"
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+const (
+	// how far around each item's rectangle is redrawn, for outlines and shadows drawn outside it
+	DIRTY_MARGIN = 8
+	// with more changed rectangles than this the whole screen is redrawn
+	DIRTY_MAX = 32
+)
+
+// DirtyRects keeps track of the parts of the screen that change between frames, so only those are
+// drawn and sent to the display.  A part has to be redrawn where an item is now and where it was in
+// the last frame.  Anything covering the whole screen means drawing all of it, as before.
+type DirtyRects struct {
+	prev   []sdl.Rect
+	full   bool         // the whole screen has to be redrawn next time
+	screen *sdl.Surface // the screen drawn on last time, a new one has nothing on it yet
+}
+
+// Invalidate makes the next frame redraw the whole screen, for changes the items do not show such as
+// a new window size or theme
+func (d *DirtyRects) Invalidate() {
+	d.full = true
+}
+
+// Changed gives the rectangles of screen to redraw to show the items in lists, or nil for the whole
+// screen
+func (d *DirtyRects) Changed(screen *sdl.Surface, lists ...*list.List) []sdl.Rect {
+	var cur []sdl.Rect
+	full := d.full || screen != d.screen
+	d.screen = screen
+	for _, items := range lists {
+		for e := items.Front(); e != nil; e = e.Next() {
+			r := dirtyRect(e.Value)
+			if r == nil {
+				continue
+			}
+			if int(r.W) >= frameW && int(r.H) >= frameH {
+				full = true
+			}
+			cur = append(cur, *r)
+		}
+	}
+	rects := append(cur, d.prev...)
+	d.prev, d.full = cur, false
+	if full || len(rects) > DIRTY_MAX {
+		return nil
+	}
+	return rects
+}
+
+// dirtyRect gives the part of the screen an item draws on, with a margin, clipped to the screen
+func dirtyRect(item interface{}) *sdl.Rect {
+	var r *sdl.Rect
+	switch d := item.(type) {
+	case interface{ DrawRect() *sdl.Rect }:
+		// markers are drawn stretched with -squash
+		r = d.DrawRect()
+	case Drawable:
+		r = d.Rect()
+	default:
+		return nil
+	}
+	x0, y0 := int(r.X)-DIRTY_MARGIN, int(r.Y)-DIRTY_MARGIN
+	x1, y1 := int(r.X)+int(r.W)+DIRTY_MARGIN, int(r.Y)+int(r.H)+DIRTY_MARGIN
+	if x0 < 0 {
+		x0 = 0
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+	if x1 > frameW {
+		x1 = frameW
+	}
+	if y1 > frameH {
+		y1 = frameH
+	}
+	if x1 <= x0 || y1 <= y0 {
+		return nil
+	}
+	return &sdl.Rect{int16(x0), int16(y0), uint16(x1 - x0), uint16(y1 - y0)}
+}
+
+// drawDirty redraws only the given parts of the screen, clearing each to the background and drawing
+// the items in lists clipped to it
+func drawDirty(screen *sdl.Surface, background *Background, rects []sdl.Rect, lists ...*list.List) {
+	for i := range rects {
+		screen.SetClipRect(&rects[i])
+		background.Fill(screen)
+		for _, items := range lists {
+			drawItems(screen, items)
+		}
+	}
+	screen.SetClipRect(nil)
+}
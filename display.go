@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// chooseDisplay tells SDL where to open the game, through the environment variables SDL 1.2 reads
+// when its video starts.  display picks the monitor a fullscreen game covers, counted from 0, and
+// -1 leaves it to SDL.  pos is where the window goes as "x,y" on the desktop, "center" to put it in
+// the middle, or "" to leave it to the window manager.
+func chooseDisplay(display int, pos string) error {
+	if display >= 0 {
+		// SDL 1.2.14 reads the first, older versions the second
+		os.Setenv("SDL_VIDEO_FULLSCREEN_DISPLAY", strconv.Itoa(display))
+		os.Setenv("SDL_VIDEO_FULLSCREEN_HEAD", strconv.Itoa(display))
+	}
+	switch pos = strings.TrimSpace(pos); pos {
+	case "":
+	case "center":
+		os.Setenv("SDL_VIDEO_CENTERED", "1")
+	default:
+		parts := strings.Split(pos, ",")
+		if len(parts) != 2 {
+			return fmt.Errorf("bad window position %q, expected x,y or center", pos)
+		}
+		for _, p := range parts {
+			if _, err := strconv.Atoi(strings.TrimSpace(p)); err != nil {
+				return fmt.Errorf("bad window position %q, expected x,y or center", pos)
+			}
+		}
+		os.Setenv("SDL_VIDEO_WINDOW_POS", strings.TrimSpace(parts[0])+","+strings.TrimSpace(parts[1]))
+	}
+	return nil
+}
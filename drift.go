@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DRIFT_MAX is the largest axis value taken for drift rather than someone holding the stick
+const DRIFT_MAX = 6000
+
+// a driftSample is the latest value of an axis and when it changed
+type driftSample struct {
+	value int16
+	since time.Time
+}
+
+// Drift learns where worn sticks rest.  An axis that sits at a small value for a while with no buttons
+// being pressed is taken to be resting there, and that value is subtracted from its readings from then
+// on.  The offsets are kept by stick name.
+type Drift struct {
+	Offsets    map[string]map[int]int16
+	last       map[[2]int]driftSample // by SDL index and axis
+	lastButton time.Time
+}
+
+// loadDrift reads the offsets saved by save.  A missing file is the same as an empty one.
+func loadDrift(path string) (*Drift, error) {
+	d := &Drift{Offsets: make(map[string]map[int]int16), last: make(map[[2]int]driftSample)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &d.Offsets); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return d, nil
+}
+
+// save the offsets as JSON
+func (d *Drift) save(path string) error {
+	data, err := json.MarshalIndent(d.Offsets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Axis records a raw axis value of the stick with SDL index which
+func (d *Drift) Axis(which, axis int, v int16, now time.Time) {
+	key := [2]int{which, axis}
+	if s, ok := d.last[key]; !ok || s.value != v {
+		d.last[key] = driftSample{v, now}
+	}
+}
+
+// Button records a button being pressed, someone is using the stick
+func (d *Drift) Button(now time.Time) {
+	d.lastButton = now
+}
+
+// Learn looks for axes that have rested at a small value for longer than wait, and takes that as their
+// new center.  It returns the axes, as SDL index and axis, that were learned.
+func (d *Drift) Learn(now time.Time, wait time.Duration, names []string) [][2]int {
+	if now.Sub(d.lastButton) < wait {
+		return nil
+	}
+	var learned [][2]int
+	for key, s := range d.last {
+		which, axis := key[0], key[1]
+		if which >= len(names) || s.value == 0 || s.value > DRIFT_MAX || s.value < -DRIFT_MAX || now.Sub(s.since) < wait {
+			continue
+		}
+		offsets := d.Offsets[names[which]]
+		if offsets == nil {
+			offsets = make(map[int]int16)
+			d.Offsets[names[which]] = offsets
+		}
+		if offsets[axis] == s.value {
+			continue
+		}
+		offsets[axis] = s.value
+		learned = append(learned, key)
+	}
+	return learned
+}
+
+// Last gives the latest raw value of an axis
+func (d *Drift) Last(which, axis int) int16 {
+	return d.last[[2]int{which, axis}].value
+}
+
+// Apply takes the learned offset off a raw axis value of the named stick
+func (d *Drift) Apply(name string, axis int, v int16) int16 {
+	off := int(d.Offsets[name][axis])
+	out := int(v) - off
+	if out > 32767 {
+		out = 32767
+	}
+	if out < -32768 {
+		out = -32768
+	}
+	return int16(out)
+}
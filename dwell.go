@@ -0,0 +1,35 @@
+package main
+
+// updateDwell counts how many updates in a row each marker has been on the same goal
+func (g *Game) updateDwell() {
+	goals := g.Visible()
+	for i := range g.Markers {
+		g.Markers[i].dwellOn(goals)
+	}
+	for i := range g.Hands {
+		g.Hands[i].dwellOn(goals)
+	}
+}
+
+// dwellOn counts another update on the first of the goals the marker is on
+func (m *Marker) dwellOn(goals []*Goal) {
+	var on *Goal
+	for _, goal := range goals {
+		if m.Intersects(goal.Rect()) {
+			on = goal
+			break
+		}
+	}
+	if on == nil || on != m.dwellGoal {
+		m.dwellGoal, m.dwell, m.touched = on, 0, nil
+	}
+	if on != nil {
+		m.dwell++
+	}
+}
+
+// Settled reports whether the marker has stayed on the goal for config.Dwell updates, long enough
+// to collect it
+func (m *Marker) Settled(goal *Goal) bool {
+	return m.dwellGoal == goal && m.dwell >= config.Dwell
+}
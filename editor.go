@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// color of the box around the selected goal in the editor
+const EDITOR_SELECT_COLOR = uint32(0x00ffff00)
+
+// ToggleEditor enters or leaves the layout editor.  While editing the game is paused, all the goals are
+// shown and the joysticks (or the mouse) move the selected goal instead of the markers.  The result
+// can be saved with the layout export key.
+func (g *Game) ToggleEditor() {
+	g.Editing = !g.Editing
+	if g.Selected < 0 || g.Selected >= len(g.Goals) {
+		g.Selected = 0
+	}
+	for _, goal := range g.Goals {
+		goal.Hidden = false
+	}
+}
+
+// SelectNext selects the next goal to edit
+func (g *Game) SelectNext() {
+	if len(g.Goals) == 0 {
+		return
+	}
+	g.Selected = (g.Selected + 1) % len(g.Goals)
+}
+
+// SelectAt selects the goal under the point x, y.  It returns false if there is none.
+func (g *Game) SelectAt(x, y int) bool {
+	p := &sdl.Rect{int16(x), int16(y), 1, 1}
+	for i, goal := range g.Goals {
+		if intersects(goal.Rect(), p) {
+			g.Selected = i
+			return true
+		}
+	}
+	return false
+}
+
+// updateEditor moves the selected goal by the combined movement of the joysticks
+func (g *Game) updateEditor() {
+	if g.Selected < 0 || g.Selected >= len(g.Goals) {
+		return
+	}
+	dx, dy := 0, 0
+	for _, m := range g.Markers {
+		mx, my := m.Step()
+		dx += mx
+		dy += my
+	}
+	goal := g.Goals[g.Selected]
+	moveGoal(goal, goal.X+dx, goal.Y+dy)
+}
+
+// moveGoal puts the goal at x, y, clamped so it stays entirely on screen
+func moveGoal(goal *Goal, x, y int) {
+	goal.X, goal.Y = clampGoal(goal, x, y)
+}
+
+// EditorItems returns the Drawables showing the editor: every goal, with a box around the selected one
+func (g *Game) EditorItems() []Drawable {
+	items := make([]Drawable, 0, len(g.Goals)+1)
+	if g.Selected >= 0 && g.Selected < len(g.Goals) {
+		items = append(items, Outline{R: *g.Goals[g.Selected].Rect(), Color: EDITOR_SELECT_COLOR, Width: 3})
+	}
+	for _, goal := range g.Goals {
+		items = append(items, goal)
+	}
+	return items
+}
+
+// An Outline is a Drawable that draws a border just outside a rectangle
+type Outline struct {
+	R     sdl.Rect
+	Color uint32
+	Width int
+}
+
+// Get the bounding rectangle of the outline
+func (o Outline) Rect() *sdl.Rect {
+	w := o.Width
+	return &sdl.Rect{o.R.X - int16(w), o.R.Y - int16(w), o.R.W + uint16(2*w), o.R.H + uint16(2*w)}
+}
+
+// Draw the outline
+func (o Outline) Draw(screen *sdl.Surface) {
+	r := o.Rect()
+	w := uint16(o.Width)
+	screen.FillRect(&sdl.Rect{r.X, r.Y, r.W, w}, o.Color)
+	screen.FillRect(&sdl.Rect{r.X, r.Y + int16(r.H-w), r.W, w}, o.Color)
+	screen.FillRect(&sdl.Rect{r.X, r.Y, w, r.H}, o.Color)
+	screen.FillRect(&sdl.Rect{r.X + int16(r.W-w), r.Y, w, r.H}, o.Color)
+}
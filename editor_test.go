@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"testing"
+)
+
+func TestEditorMovesSelectedGoal(t *testing.T) {
+	// with a speed of 10 the hat moves 4 pixels an update
+	useConfig(t, Config{Speed: 10})
+	tests := []struct {
+		name         string
+		x, y         int
+		hat          uint8
+		updates      int
+		wantX, wantY int
+	}{
+		{"right", 100, 100, sdl.HAT_RIGHT, 5, 120, 100},
+		{"up", 100, 100, sdl.HAT_UP, 2, 100, 92},
+		{"stops at the left edge", 30, 100, sdl.HAT_LEFT, 10, 20, 100},
+		{"stops at the bottom", 100, HEIGHT - 25, sdl.HAT_DOWN, 10, 100, HEIGHT - 20},
+	}
+	for _, tt := range tests {
+		goals := []*Goal{{X: 500, Y: 500, W: 40, H: 40}, {X: tt.x, Y: tt.y, W: 40, H: 40}}
+		g := NewGame([]Marker{{}}, goals)
+		g.ToggleEditor()
+		g.SelectNext()
+		g.Markers[0].Hat(tt.hat)
+		for i := 0; i < tt.updates; i++ {
+			g.Update()
+		}
+		if goals[1].X != tt.wantX || goals[1].Y != tt.wantY {
+			t.Errorf("%s: goal at %d,%d, want %d,%d", tt.name, goals[1].X, goals[1].Y, tt.wantX, tt.wantY)
+		}
+		if goals[0].X != 500 || goals[0].Y != 500 {
+			t.Errorf("%s: the goal not selected moved", tt.name)
+		}
+	}
+}
+
+func TestEditorSelect(t *testing.T) {
+	g := NewGame(nil, []*Goal{{X: 100, Y: 100, W: 40, H: 40}, {X: 300, Y: 100, W: 40, H: 40}})
+	g.ToggleEditor()
+	tests := []struct {
+		x, y     int
+		wantOK   bool
+		selected int
+	}{
+		{300, 100, true, 1},
+		{600, 600, false, 1},
+		{90, 110, true, 0},
+	}
+	for _, tt := range tests {
+		if ok := g.SelectAt(tt.x, tt.y); ok != tt.wantOK || g.Selected != tt.selected {
+			t.Errorf("SelectAt(%d, %d) = %v selecting %d, want %v selecting %d", tt.x, tt.y, ok, g.Selected, tt.wantOK, tt.selected)
+		}
+	}
+	g.SelectNext()
+	g.SelectNext()
+	if g.Selected != 0 {
+		t.Errorf("selecting past the last goal gave %d, want 0", g.Selected)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	_ "embed"
+)
+
+// the font used when there is no other, DejaVu Sans (see fonts/LICENSE)
+//
+//go:embed fonts/default.ttf
+var embeddedFont []byte
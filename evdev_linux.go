@@ -0,0 +1,273 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// evdev event types and codes, from linux/input-event-codes.h
+const (
+	EV_KEY    = 0x01
+	EV_ABS    = 0x03
+	EV_FF     = 0x15
+	FF_RUMBLE = 0x50
+	BTN_MISC  = 0x100 // first button that is not a keyboard key
+	BTN_JOY   = 0x120 // first joystick button, BTN_JOYSTICK
+	KEY_MAX   = 0x2ff
+	ABS_HAT0X = 0x10
+	ABS_HAT0Y = 0x11
+	ABS_HAT3Y = 0x17
+	ABS_MAX   = 0x3f
+)
+
+// inputEvent is struct input_event.  The time is a struct timeval, so its size depends on the
+// platform's long.
+type inputEvent struct {
+	Time  syscall.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// the rumble played when a goal is collected: how long it lasts in milliseconds and how strong the
+// big and small motors run, out of 0xffff
+const (
+	RUMBLE_TIME   = 200
+	RUMBLE_STRONG = 0xc000
+	RUMBLE_WEAK   = 0x8000
+)
+
+// ffEffect is struct ff_effect holding a rumble.  The effect union starts on a long boundary and is as
+// big as its largest member, struct ff_periodic_effect, which ends with a pointer.
+type ffEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+	Trigger   [2]uint16 // button and interval
+	Replay    [2]uint16 // length and delay, in milliseconds
+	_         uint16
+	Rumble    [2]uint16 // strong and weak magnitude, struct ff_rumble_effect
+	_         [20]byte
+	_         uintptr
+}
+
+// absInfo is struct input_absinfo
+type absInfo struct {
+	Value, Minimum, Maximum, Fuzz, Flat, Resolution int32
+}
+
+// EvdevInput reads joysticks straight from the kernel's event devices, bypassing SDL's joystick
+// handling.  SDL's other events (keyboard, window) are passed on as they are.  Device numbers are the
+// order of the paths.
+type EvdevInput struct {
+	Paths   []string
+	events  chan interface{}
+	files   []*os.File
+	effects []int16 // the rumble uploaded to each device, -1 if it cannot rumble
+}
+
+// OpenEvdev starts reading the event devices.  "auto" finds the joysticks udev lists in
+// /dev/input/by-id, otherwise paths is a list of device files.
+func OpenEvdev(paths []string, sdlEvents <-chan interface{}) (*EvdevInput, error) {
+	if len(paths) == 1 && paths[0] == "auto" {
+		paths, _ = filepath.Glob("/dev/input/by-id/*-event-joystick")
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("evdev: no joysticks found in /dev/input/by-id")
+		}
+	}
+	ev := &EvdevInput{Paths: paths, events: make(chan interface{}, 64)}
+	for dev, path := range paths {
+		// playing force feedback needs write access, the sticks can still be read without it
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			f, err = os.Open(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("evdev joystick", dev+1, path)
+		effect := int16(-1)
+		if config.Rumble {
+			effect = uploadRumble(f)
+		}
+		ev.files = append(ev.files, f)
+		ev.effects = append(ev.effects, effect)
+		go ev.read(dev, f)
+	}
+	go func() {
+		for e := range sdlEvents {
+			switch e.(type) {
+			case sdl.JoyAxisEvent, sdl.JoyButtonEvent, sdl.JoyHatEvent, sdl.JoyBallEvent:
+				// the sticks are read directly
+			default:
+				ev.events <- e
+			}
+		}
+	}()
+	return ev, nil
+}
+
+func (ev *EvdevInput) Events() <-chan interface{} {
+	return ev.events
+}
+
+// uploadRumble gives the device the rumble effect, EVIOCSFF.  It returns the effect's id, or -1 if the
+// device has no force feedback.
+func uploadRumble(f *os.File) int16 {
+	e := ffEffect{Type: FF_RUMBLE, ID: -1, Replay: [2]uint16{RUMBLE_TIME, 0}, Rumble: [2]uint16{RUMBLE_STRONG, RUMBLE_WEAK}}
+	req := 1<<30 | unsafe.Sizeof(e)<<16 | 'E'<<8 | 0x80
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(&e))); errno != 0 {
+		return -1
+	}
+	return e.ID
+}
+
+// Rumble plays the rumble on device dev, if it can
+func (ev *EvdevInput) Rumble(dev int) {
+	if ev == nil || dev < 0 || dev >= len(ev.effects) || ev.effects[dev] < 0 {
+		return
+	}
+	e := inputEvent{Type: EV_FF, Code: uint16(ev.effects[dev]), Value: 1}
+	// a device that went away or was opened read only just does not rumble
+	ev.files[dev].Write(unsafe.Slice((*byte)(unsafe.Pointer(&e)), unsafe.Sizeof(e)))
+}
+
+// read the events of one device until it goes away
+func (ev *EvdevInput) read(dev int, f *os.File) {
+	defer f.Close()
+	buttons := buttonNumbers(eventBits(f, EV_KEY, KEY_MAX))
+	axes := axisNumbers(eventBits(f, EV_ABS, ABS_MAX))
+	ranges := make(map[uint16]absInfo)
+	hats := make(map[int]uint8)
+	var e inputEvent
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(&e)), unsafe.Sizeof(e))
+	for {
+		if _, err := f.Read(buf); err != nil {
+			fmt.Println("evdev:", err)
+			return
+		}
+		typ, code, value := e.Type, e.Code, e.Value
+		switch {
+		case typ == EV_KEY && value != 2:
+			// value 2 is auto repeat
+			button, ok := buttons[code]
+			if !ok {
+				continue
+			}
+			t := uint8(sdl.JOYBUTTONUP)
+			if value == 1 {
+				t = sdl.JOYBUTTONDOWN
+			}
+			ev.events <- sdl.JoyButtonEvent{Type: t, Which: uint8(dev), Button: button, State: uint8(value)}
+		case typ == EV_ABS && code >= ABS_HAT0X && code <= ABS_HAT3Y:
+			hat := int(code-ABS_HAT0X) / 2
+			hats[hat] = hatBits(hats[hat], code%2 == 0, value)
+			ev.events <- sdl.JoyHatEvent{Type: sdl.JOYHATMOTION, Which: uint8(dev), Hat: uint8(hat), Value: hats[hat]}
+		case typ == EV_ABS:
+			axis, ok := axes[code]
+			if !ok {
+				continue
+			}
+			info, ok := ranges[code]
+			if !ok {
+				info = axisRange(f, code)
+				ranges[code] = info
+			}
+			ev.events <- sdl.JoyAxisEvent{Type: sdl.JOYAXISMOTION, Which: uint8(dev), Axis: axis, Value: scaleAxis(value, info)}
+		}
+	}
+}
+
+// eventBits asks the kernel which codes up to max of event type typ the device has, EVIOCGBIT.  The
+// result is a bit for each code.
+func eventBits(f *os.File, typ, max int) []byte {
+	bits := make([]byte, max/8+1)
+	req := 2<<30 | uintptr(len(bits))<<16 | 'E'<<8 | uintptr(0x20+typ)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(&bits[0]))); errno != 0 {
+		fmt.Println("evdev: cannot get the device's capabilities:", errno)
+	}
+	return bits
+}
+
+// hasBit reports whether bit n is set
+func hasBit(bits []byte, n int) bool {
+	return n/8 < len(bits) && bits[n/8]&(1<<(n%8)) != 0
+}
+
+// buttonNumbers numbers the buttons the device has the way SDL does, so button numbers (and so
+// remapping and profiles) are the same as without -evdev.  The joystick and gamepad buttons come
+// first, in the order of their codes, then the other buttons.
+func buttonNumbers(keys []byte) map[uint16]uint8 {
+	numbers := make(map[uint16]uint8)
+	add := func(from, to int) {
+		for code := from; code < to; code++ {
+			if hasBit(keys, code) {
+				numbers[uint16(code)] = uint8(len(numbers))
+			}
+		}
+	}
+	add(BTN_JOY, KEY_MAX)
+	add(BTN_MISC, BTN_JOY)
+	return numbers
+}
+
+// axisNumbers numbers the axes the device has the way SDL does, in the order of their codes.  The
+// hats are not axes.
+func axisNumbers(abs []byte) map[uint16]uint8 {
+	numbers := make(map[uint16]uint8)
+	for code := 0; code < ABS_MAX; code++ {
+		if code >= ABS_HAT0X && code <= ABS_HAT3Y {
+			continue
+		}
+		if hasBit(abs, code) {
+			numbers[uint16(code)] = uint8(len(numbers))
+		}
+	}
+	return numbers
+}
+
+// axisRange asks the kernel for the range of an axis, EVIOCGABS
+func axisRange(f *os.File, code uint16) absInfo {
+	var info absInfo
+	req := uintptr(2<<30 | unsafe.Sizeof(info)<<16 | 'E'<<8 | (0x40 + uintptr(code)))
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(unsafe.Pointer(&info))); errno != 0 {
+		// assume the full SDL range
+		return absInfo{Minimum: -32768, Maximum: 32767}
+	}
+	return info
+}
+
+// scaleAxis stretches an axis value over SDL's range of -32768 to 32767
+func scaleAxis(v int32, info absInfo) int16 {
+	span := int64(info.Maximum) - int64(info.Minimum)
+	if span <= 0 {
+		return 0
+	}
+	return int16((int64(v)-int64(info.Minimum))*65535/span - 32768)
+}
+
+// hatBits updates an SDL hat position with one of its axes, x if horizontal
+func hatBits(bits uint8, horizontal bool, value int32) uint8 {
+	if horizontal {
+		bits &^= sdl.HAT_LEFT | sdl.HAT_RIGHT
+		if value < 0 {
+			bits |= sdl.HAT_LEFT
+		} else if value > 0 {
+			bits |= sdl.HAT_RIGHT
+		}
+	} else {
+		bits &^= sdl.HAT_UP | sdl.HAT_DOWN
+		if value < 0 {
+			bits |= sdl.HAT_UP
+		} else if value > 0 {
+			bits |= sdl.HAT_DOWN
+		}
+	}
+	return bits
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// bitsFor makes the capability bits for the given codes
+func bitsFor(max int, codes ...int) []byte {
+	bits := make([]byte, max/8+1)
+	for _, c := range codes {
+		bits[c/8] |= 1 << (c % 8)
+	}
+	return bits
+}
+
+func TestButtonNumbers(t *testing.T) {
+	// a gamepad with A, B, X, Y, start, and a misc button numbered after them
+	const BTN_0, BTN_A, BTN_B, BTN_X, BTN_Y, BTN_START = 0x100, 0x130, 0x131, 0x133, 0x134, 0x13b
+	got := buttonNumbers(bitsFor(KEY_MAX, BTN_0, BTN_A, BTN_B, BTN_X, BTN_Y, BTN_START))
+	want := map[uint16]uint8{BTN_A: 0, BTN_B: 1, BTN_X: 2, BTN_Y: 3, BTN_START: 4, BTN_0: 5}
+	if len(got) != len(want) {
+		t.Fatalf("numbered %d buttons, want %d", len(got), len(want))
+	}
+	for code, n := range want {
+		if got[code] != n {
+			t.Errorf("button %#x is %d, want %d", code, got[code], n)
+		}
+	}
+}
+
+func TestAxisNumbers(t *testing.T) {
+	const ABS_X, ABS_Y, ABS_RX, ABS_RY, ABS_THROTTLE = 0x00, 0x01, 0x03, 0x04, 0x06
+	got := axisNumbers(bitsFor(ABS_MAX, ABS_X, ABS_Y, ABS_RX, ABS_RY, ABS_HAT0X, ABS_HAT0Y, ABS_THROTTLE, 0x28))
+	want := map[uint16]uint8{ABS_X: 0, ABS_Y: 1, ABS_RX: 2, ABS_RY: 3, ABS_THROTTLE: 4, 0x28: 5}
+	if len(got) != len(want) {
+		t.Fatalf("numbered %d axes, want %d", len(got), len(want))
+	}
+	for code, n := range want {
+		if got[code] != n {
+			t.Errorf("axis %#x is %d, want %d", code, got[code], n)
+		}
+	}
+}
+
+func TestInputEventSize(t *testing.T) {
+	// struct input_event is two longs and then 8 bytes
+	if got, want := unsafe.Sizeof(inputEvent{}), 2*unsafe.Sizeof(uintptr(0))+8; got != want {
+		t.Errorf("input_event is %d bytes, want %d", got, want)
+	}
+}
+
+func TestFFEffectSize(t *testing.T) {
+	// struct ff_effect is 48 bytes with 64 bit pointers and 44 with 32 bit ones
+	want := uintptr(48)
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		want = 44
+	}
+	if got := unsafe.Sizeof(ffEffect{}); got != want {
+		t.Errorf("ff_effect is %d bytes, want %d", got, want)
+	}
+}
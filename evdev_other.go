@@ -0,0 +1,24 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+)
+
+// EvdevInput reads joysticks from the kernel's event devices, which only Linux has
+type EvdevInput struct {
+	Paths []string
+}
+
+// OpenEvdev fails, evdev is Linux only
+func OpenEvdev(paths []string, sdlEvents <-chan interface{}) (*EvdevInput, error) {
+	return nil, fmt.Errorf("evdev is only available on Linux")
+}
+
+func (ev *EvdevInput) Events() <-chan interface{} {
+	return nil
+}
+
+// Rumble does nothing, there is no force feedback without evdev
+func (ev *EvdevInput) Rumble(dev int) {}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"unsafe"
+)
+
+// Post processing filters applied to the finished frame
+const (
+	FILTER_NORMAL   = "normal"   // leave the frame alone
+	FILTER_CONTRAST = "contrast" // push the colors away from mid grey
+	FILTER_INVERT   = "invert"   // invert the colors
+)
+
+// how much the contrast filter scales the distance of each channel from mid grey
+const CONTRAST_GAIN = 2
+
+// postProcess applies filter to every pixel of a 32 bit surface.  Other depths are left alone.
+func postProcess(s *sdl.Surface, filter string) {
+	if filter == FILTER_NORMAL || filter == "" || s.Format.BytesPerPixel != 4 {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	for y := 0; y < int(s.H); y++ {
+		row := unsafe.Pointer(uintptr(s.Pixels) + uintptr(y*int(s.Pitch)))
+		pixels := unsafe.Slice((*uint32)(row), int(s.W))
+		for x, p := range pixels {
+			pixels[x] = transformPixel(p, filter)
+		}
+	}
+}
+
+// transformPixel applies filter to a 0x00RRGGBB pixel
+func transformPixel(p uint32, filter string) uint32 {
+	switch filter {
+	case FILTER_INVERT:
+		return ^p & 0x00ffffff
+	case FILTER_CONTRAST:
+		var out uint32
+		for shift := uint(0); shift < 24; shift += 8 {
+			c := int((p>>shift)&0xff) - 128
+			out |= uint32(clamp(c*CONTRAST_GAIN+128, 0, 255)) << shift
+		}
+		return out
+	}
+	return p
+}
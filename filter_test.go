@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"testing"
+)
+
+func TestTransformPixel(t *testing.T) {
+	tests := []struct {
+		filter string
+		in     uint32
+		want   uint32
+	}{
+		{FILTER_NORMAL, 0x123456, 0x123456},
+		{FILTER_INVERT, 0x000000, 0xffffff},
+		{FILTER_INVERT, 0x123456, 0xedcba9},
+		{FILTER_CONTRAST, 0x808080, 0x808080},
+		{FILTER_CONTRAST, 0x90ff00, 0xa0ff00},
+	}
+	for _, tt := range tests {
+		if got := transformPixel(tt.in, tt.filter); got != tt.want {
+			t.Errorf("transformPixel(%06x, %s) = %06x, want %06x", tt.in, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestPostProcess(t *testing.T) {
+	s := sdl.CreateRGBSurface(sdl.SWSURFACE, 3, 2, 32, 0xff0000, 0xff00, 0xff, 0)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			*pixelPtr(s, x, y) = uint32(x*0x10 + y)
+		}
+	}
+	postProcess(s, FILTER_INVERT)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			if got, want := pixelAt(s, x, y), ^uint32(x*0x10+y)&0xffffff; got != want {
+				t.Errorf("pixel %d,%d = %06x, want %06x", x, y, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// how long the finish screen ignores buttons, so one still held from the last goal does not skip it
+	FINISH_WAIT = 2 * time.Second
+	// how many pieces of confetti fall
+	CONFETTI = 120
+	// how often a firework goes off, and how long it lasts
+	FIREWORK_EVERY = 700 * time.Millisecond
+	FIREWORK_TIME  = 1400 * time.Millisecond
+	// how many sparks a firework has and how far they fly
+	FIREWORK_SPARKS = 24
+	FIREWORK_SIZE   = 150
+)
+
+// finish stops the game after the last goal of a round, to show the finish screen until a player
+// presses a button
+func (g *Game) finish() {
+	g.Finished = true
+	g.finishedAt = g.Clock
+	g.RoundTime = g.Clock - g.roundStart
+}
+
+// CanPlayAgain tells whether the finish screen is ready for a button to start the next round
+func (g *Game) CanPlayAgain() bool {
+	return g.Finished && g.Clock-g.finishedAt >= FINISH_WAIT
+}
+
+// PlayAgain leaves the finish screen and starts the next round as config.EndPolicy says
+func (g *Game) PlayAgain() {
+	if !g.CanPlayAgain() {
+		return
+	}
+	g.Finished = false
+	g.endOfSequence(config.EndPolicy)
+	g.showGoal()
+}
+
+// a piece of confetti, where it starts and how it falls
+type confetto struct {
+	x, y      float64
+	speed     float64 // pixels a second
+	sway      float64 // phase of its side to side drift
+	w, h      int
+	colorStep int
+}
+
+// FinishScreen is shown over the game when a round is finished: confetti and fireworks, how long the
+// round took and a prompt to play again
+type FinishScreen struct {
+	title, time, prompt *Label
+	confetti            []confetto
+	elapsed             time.Duration // how long the screen has been up
+}
+
+// Create the finish screen for the round g has just finished
+func NewFinishScreen(font, bigFont *ttf.Font, g *Game) *FinishScreen {
+	f := &FinishScreen{
+		title:  &Label{Font: bigFont, Color: sdl.Color{255, 255, 0, 0}},
+		time:   &Label{Font: font, Color: config.TextColor},
+		prompt: &Label{Font: font, Color: config.TextColor},
+	}
+	f.title.SetText("All done!")
+	f.time.SetText("time: " + clockText(g.RoundTime))
+	f.title.X, f.title.Y = (frameW-int(f.title.Rect().W))/2, frameH/2-int(f.title.Rect().H)-10
+	f.time.X, f.time.Y = (frameW-int(f.time.Rect().W))/2, frameH/2+10
+	// the confetti is the same every time, it does not need the game's random numbers
+	rng := rand.New(rand.NewSource(1))
+	f.confetti = make([]confetto, CONFETTI)
+	for i := range f.confetti {
+		f.confetti[i] = confetto{
+			x: rng.Float64() * float64(frameW), y: -rng.Float64() * float64(frameH),
+			speed: 80 + rng.Float64()*120, sway: rng.Float64() * 2 * math.Pi,
+			w: 6 + rng.Intn(6), h: 4 + rng.Intn(4), colorStep: rng.Intn(len(celebrateColors)),
+		}
+	}
+	return f
+}
+
+// Update the screen for the game's clock
+func (f *FinishScreen) Update(g *Game) {
+	f.elapsed = g.Clock - g.finishedAt
+	if g.CanPlayAgain() {
+		f.prompt.SetText("press a button to play again")
+		f.prompt.X, f.prompt.Y = (frameW-int(f.prompt.Rect().W))/2, f.time.Y+int(f.time.Rect().H)+20
+	}
+}
+
+// Get the area the screen covers
+func (f *FinishScreen) Rect() *sdl.Rect {
+	return frameRect()
+}
+
+// Draw the fireworks, the confetti and the text over the game
+func (f *FinishScreen) Draw(screen *sdl.Surface) {
+	t := f.elapsed.Seconds()
+	f.drawFireworks(screen)
+	for _, c := range f.confetti {
+		// fall, and start again from the top after going off the bottom
+		y := math.Mod(c.y+c.speed*t, float64(frameH+frameH/2))
+		if y < 0 {
+			continue
+		}
+		x := c.x + 30*math.Sin(c.sway+2*t)
+		color := celebrateColors[c.colorStep]
+		screen.FillRect(&sdl.Rect{int16(x), int16(y), uint16(c.w), uint16(c.h)}, color)
+	}
+	// a box behind the text so it can be read over everything else
+	bottom := f.time.Y + int(f.time.Rect().H)
+	if f.prompt.Rect().W > 0 {
+		bottom = f.prompt.Y + int(f.prompt.Rect().H)
+	}
+	top := f.title.Y
+	screen.FillRect(&sdl.Rect{int16(frameW/2 - 300), int16(top - 20), 600, uint16(bottom - top + 40)}, config.BackgroundColor)
+	f.title.Draw(screen)
+	f.time.Draw(screen)
+	f.prompt.Draw(screen)
+}
+
+// drawFireworks draws the fireworks going off, each a ring of sparks flying out from a spot that
+// comes from its number
+func (f *FinishScreen) drawFireworks(screen *sdl.Surface) {
+	last := int(f.elapsed / FIREWORK_EVERY)
+	for n := last; n >= 0 && f.elapsed-time.Duration(n)*FIREWORK_EVERY < FIREWORK_TIME; n-- {
+		age := float64(f.elapsed-time.Duration(n)*FIREWORK_EVERY) / float64(FIREWORK_TIME)
+		rng := rand.New(rand.NewSource(int64(n)))
+		cx := 100 + rng.Float64()*float64(frameW-200)
+		cy := 80 + rng.Float64()*float64(frameH/2)
+		color := celebrateColors[rng.Intn(len(celebrateColors))]
+		// sparks slow down and shrink as they fly out
+		r := FIREWORK_SIZE * math.Sqrt(age)
+		size := int(6 * (1 - age))
+		if size < 1 {
+			continue
+		}
+		for i := 0; i < FIREWORK_SPARKS; i++ {
+			a := 2 * math.Pi * float64(i) / FIREWORK_SPARKS
+			x, y := cx+r*math.Cos(a), cy+r*math.Sin(a)
+			screen.FillRect(&sdl.Rect{int16(x) - int16(size/2), int16(y) - int16(size/2), uint16(size), uint16(size)}, color)
+		}
+	}
+}
+
+// Free the screen's text
+func (f *FinishScreen) Free() {
+	f.title.Free()
+	f.time.Free()
+	f.prompt.Free()
+}
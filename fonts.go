@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// where fonts are usually found when there is no font.ttf
+var systemFonts = []string{
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans-Bold.ttf",
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/TTF/DejaVuSans.ttf",
+	"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/truetype/liberation/LiberationSans-Regular.ttf",
+	"/usr/share/fonts/truetype/freefont/FreeSans.ttf",
+	"/usr/share/fonts/gnu-free/FreeSans.ttf",
+	"/System/Library/Fonts/Supplemental/Arial.ttf",
+	"/Library/Fonts/Arial.ttf",
+	`C:\Windows\Fonts\arial.ttf`,
+}
+
+// fallbackFont finds a font to use when the font asset is missing: the built in one, written out to a
+// temporary file as SDL_ttf only opens files, or if that cannot be done a system font.
+// It gives the file and whether it is temporary.
+func fallbackFont() (string, bool, error) {
+	if path, err := writeTemp(embeddedFont, "gojoystick-*.ttf"); err == nil {
+		return path, true, nil
+	}
+	for _, path := range systemFonts {
+		if _, err := os.Stat(filepath.FromSlash(path)); err == nil {
+			return path, false, nil
+		}
+	}
+	return "", false, errors.New("no font found, put a font.ttf in the asset directory or give one with -font")
+}
+
+// writeTemp writes data to a new temporary file named like pattern and gives its path
+func writeTemp(data []byte, pattern string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
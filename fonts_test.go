@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestEmbeddedFont(t *testing.T) {
+	// a TrueType font starts with version 1.0
+	if !bytes.HasPrefix(embeddedFont, []byte{0, 1, 0, 0}) {
+		t.Fatalf("the built in font is not a TrueType font")
+	}
+	path, temp, err := fallbackFont()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !temp {
+		t.Fatalf("fallback font %s is not the built in one", path)
+	}
+	defer os.Remove(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, embeddedFont) {
+		t.Errorf("the font written out is not the built in one")
+	}
+}
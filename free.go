@@ -0,0 +1,15 @@
+package main
+
+// freeGoal finds a goal a player is on in free play.  The goals not yet collected are kept at the end
+// of Goals, from CurGoal on, so the goal found is swapped to CurGoal.  Collecting it then works the
+// same as in the other modes.
+func (g *Game) freeGoal() *Goal {
+	for i := g.CurGoal; i < len(g.Goals); i++ {
+		if g.Goals[i].Hidden || g.playerOn(g.Goals[i]) < 0 {
+			continue
+		}
+		g.Goals[g.CurGoal], g.Goals[i] = g.Goals[i], g.Goals[g.CurGoal]
+		return g.Goals[g.CurGoal]
+	}
+	return nil
+}
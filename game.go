@@ -0,0 +1,317 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math/rand"
+	"time"
+)
+
+// What happens after the last goal is collected
+const (
+	END_LOOP = "loop" // start over with the same goals (or the next word)
+	END_STOP = "stop" // the game is won, show the win screen
+	END_NEXT = "next" // start a new round with the goals moved (or the next word)
+)
+
+// Game holds the state of a session: the players' markers, the goals and the progress through them.
+type Game struct {
+	Markers   []Marker
+	Hands     []Marker // each player's second marker, driven by their right stick, with -two-hands
+	Goals     []*Goal
+	CurGoal   int           // index of the goal to collect next
+	Misses    int           // goals that timed out before anyone reached them
+	Collected int           // goals collected this session
+	Clock     time.Duration // simulated time since the game started, advanced by Step
+	Editing   bool          // the layout editor is open
+	Selected  int           // the goal being moved in the editor
+	Word      int           // index into config.Words of the word being spelled
+	Maze      *Maze         // the maze in maze mode, nil otherwise
+	Objects   []Collidable  // obstacles and anything else the markers can run into
+	Lanes     []Lane        // each player's own goals in race mode
+	Winner    int           // the player who won the race
+	Level     float64       // how hard adaptive difficulty has made the game, 0 to 1
+	Cleared   int           // how many times the canvas was cleared in paint mode
+	Particles *Particles    // the bursts of collected goals, nil when they are turned off
+	Won       bool          // all the goals were collected and the game stopped
+	Finished  bool          // the round is over and the finish screen waits for a button to play again
+	RoundTime time.Duration // how long the last finished round took
+	Idle      bool          // nobody has touched anything for a while, the game waits for input
+	Paused    bool          // a player paused the game
+	TestMode  bool          // assists are turned off to measure unaided performance
+
+	// MakeGoals builds the goals for a word, it is needed to move on to the next word
+	MakeGoals func(src string, rng *rand.Rand) []*Goal
+	// Sprite, if set, gives the image with the given asset name, for the players' sprites
+	Sprite func(name string) *sdl.Surface
+	// OnGoals, if set, is called with new goals when they are made, and how many goals they are out of
+	OnGoals func(goals []*Goal, count int)
+	// OnCollect, if set, is called when a goal is collected
+	OnCollect func(goal *Goal, count int)
+	// OnRumble, if set, is called with the player who collected a goal, to shake their controller
+	OnRumble func(player int)
+	// OnEvent, if set, is told about things happening in the game: "collect <goal>", "wrong <goal>",
+	// "round", "won", "obstacle <player>", and "join <player>" or "leave <player>" when joysticks are
+	// plugged in or out
+	OnEvent func(event string)
+
+	goalShown  time.Duration    // when the current goal appeared (or will appear)
+	recent     placeHistory     // where goals were recently moved to
+	wrongGoal  *Goal            // the wrong goal last touched in ordered mode
+	wrongUntil time.Duration    // when it stops flashing
+	touching   map[contact]bool // the markers touching Objects at the last update
+	simon      *Simon           // the simon says game, once started
+	track      *Tracker         // the tracking exercise, once started
+
+	celebrateUntil time.Duration // when the current celebration ends
+	roundStart     time.Duration // when the current round started
+	finishedAt     time.Duration // when the finish screen came up
+	bigCelebration bool
+	started        bool
+	rng            *rand.Rand // all the game's randomness comes from here, so Step is repeatable
+}
+
+// Create a new game with the given markers and goals
+func NewGame(markers []Marker, goals []*Goal) *Game {
+	g := &Game{Markers: markers, Goals: goals, TestMode: config.TestMode, rng: rand.New(rand.NewSource(config.Seed))}
+	if config.Particles {
+		g.Particles = NewParticles()
+	}
+	return g
+}
+
+// Get the goal to be collected next, or nil if there is none to show right now
+func (g *Game) Current() *Goal {
+	if g.CurGoal < 0 || g.CurGoal >= len(g.Goals) {
+		return nil
+	}
+	goal := g.Goals[g.CurGoal]
+	if goal.Hidden {
+		return nil
+	}
+	return goal
+}
+
+// Remaining gives how many goals are left to collect this round
+func (g *Game) Remaining() int {
+	if g.CurGoal >= len(g.Goals) {
+		return 0
+	}
+	return len(g.Goals) - g.CurGoal
+}
+
+// Does the game need to be updated every frame, even when none of the markers are moving
+func (g *Game) Animating() bool {
+	if config.Mode == MODE_WHACK || config.Mode == MODE_TRACK && !g.Won || config.GoalSpeed > 0 || g.Editing || g.Celebrating() || g.RevealProgress() < 1 ||
+		g.Clock < g.wrongUntil || g.simonShowing() || g.Particles.Active() || g.Pulsing() || g.Finished {
+		return true
+	}
+	for i := range g.Markers {
+		if g.Markers[i].ButtonHeld(config.TractorButton) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update the game by one step.  Moves the markers and checks them against the current goal.  This is
+// normally called through Step.
+func (g *Game) Update() {
+	if !g.started {
+		g.started = true
+		g.showGoal()
+	}
+	if g.Idle || g.Paused {
+		return
+	}
+	if g.Particles != nil {
+		g.Particles.Update(g.Clock)
+	}
+	if g.Editing {
+		g.updateEditor()
+		return
+	}
+	defer g.forgetPresses()
+	for i := range g.Markers {
+		g.Markers[i].Update()
+	}
+	for i := range g.Hands {
+		g.Hands[i].Update()
+	}
+	if config.Mode == MODE_PAINT {
+		return
+	}
+	g.moveGoals()
+	g.updateDwell()
+	g.checkObjects()
+	if g.Won || g.Finished {
+		return
+	}
+	if config.Mode == MODE_RACE {
+		g.updateRace()
+		return
+	}
+	if config.Mode == MODE_SIMON {
+		g.updateSimon()
+		return
+	}
+	if config.Mode == MODE_TRACK {
+		g.updateTrack()
+		return
+	}
+	if config.Mode == MODE_WHACK {
+		g.updateWhack()
+	}
+	if config.Mode == MODE_ORDERED {
+		g.checkWrongGoals()
+	}
+	g.applyTractor()
+
+	goal := g.Current()
+	if config.Mode == MODE_FREE {
+		goal = g.freeGoal()
+	}
+	if goal == nil || !g.Collectable() {
+		return
+	}
+	hit := -1
+	if config.Coop {
+		if !g.everyoneOn(goal) {
+			return
+		}
+	} else if hit = g.playerOn(goal); hit < 0 {
+		return
+	}
+	points := goalPoints(g.Clock - g.goalShown)
+	if config.Mode == MODE_WHACK {
+		points = whackPoints(g.Clock-g.goalShown, config.GoalTimeout)
+	}
+	if config.Adapt {
+		g.adapt((g.Clock - g.goalShown).Seconds())
+	}
+	if config.Coop {
+		// they got there together, so they all get the points
+		for i := range g.Markers {
+			g.Markers[i].Score += points
+			g.rumble(i)
+		}
+	} else {
+		g.Markers[hit].Score += points
+		g.rumble(hit)
+	}
+	g.collected()
+	g.advance()
+}
+
+// playerOn gives the player on the goal and able to collect it, or -1
+func (g *Game) playerOn(goal *Goal) int {
+	r := goal.Rect()
+	for i := range g.Markers {
+		if g.Markers[i].Intersects(r) && g.Markers[i].Settled(goal) && g.Markers[i].CanCollect() {
+			return i
+		}
+	}
+	return g.handOn(goal)
+}
+
+// advance moves on to the next goal, starting a new round after the last one
+func (g *Game) advance() {
+	g.CurGoal++
+	if g.CurGoal >= len(g.Goals) {
+		if config.FinishScreen && config.EndPolicy != END_STOP {
+			// the finish screen starts the next round when somebody presses a button
+			g.finish()
+			return
+		}
+		if !g.endOfSequence(config.EndPolicy) {
+			return
+		}
+	}
+	g.showGoal()
+}
+
+// endOfSequence handles running out of goals according to policy, one of the END_* constants.  It
+// returns false if the game is over.
+func (g *Game) endOfSequence(policy string) bool {
+	switch policy {
+	case END_STOP:
+		g.CurGoal = len(g.Goals)
+		g.Won = true
+		g.event("won")
+		return false
+	case END_NEXT:
+		g.CurGoal = 0
+		if len(config.Words) > 0 {
+			g.nextWord()
+		} else {
+			g.reshuffle()
+		}
+	default:
+		g.CurGoal = 0
+		if len(config.Words) > 0 {
+			g.nextWord()
+		}
+	}
+	g.newRound()
+	return true
+}
+
+// showGoal prepares the current goal to be shown
+func (g *Game) showGoal() {
+	g.goalShown = g.Clock
+	if goal := g.Current(); config.Adapt && goal != nil {
+		g.adaptGoal(goal)
+	}
+	if config.Mode == MODE_WHACK {
+		g.spawnWhack()
+	}
+}
+
+// goalsMade tells OnGoals about new goals out of count
+func (g *Game) goalsMade(goals []*Goal, count int) {
+	if g.OnGoals != nil {
+		g.OnGoals(goals, count)
+	}
+}
+
+// rumble tells OnRumble that player collected a goal
+func (g *Game) rumble(player int) {
+	if g.OnRumble != nil {
+		g.OnRumble(player)
+	}
+}
+
+// event reports something happening in the game to OnEvent
+func (g *Game) event(event string) {
+	if g.OnEvent != nil {
+		g.OnEvent(event)
+	}
+}
+
+// Restart the round from the first goal, with the goals moved to new places
+func (g *Game) Restart() {
+	g.reshuffle()
+	g.CurGoal = 0
+	g.Won = false
+	g.Finished = false
+	g.newRound()
+	g.showGoal()
+}
+
+// reshuffle moves the goals to new random places, unless a maze or a layout put them where they are
+func (g *Game) reshuffle() {
+	if g.Maze != nil || config.LayoutPath != "" {
+		return
+	}
+	for _, goal := range g.Goals {
+		placeRandom(goal, &g.recent, g.rng)
+	}
+}
+
+// newRound resets the per round counters
+func (g *Game) newRound() {
+	g.event("round")
+	g.roundStart = g.Clock
+	for i := range g.Markers {
+		g.Markers[i].Presses = 0
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEndOfSequence(t *testing.T) {
+	tests := []struct {
+		policy    string
+		wantGoal  int
+		wantWon   bool
+		wantMoved bool
+	}{
+		{END_LOOP, 0, false, false},
+		{END_NEXT, 0, false, true},
+		{END_STOP, 2, true, false},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{EndPolicy: tt.policy, CollectButton: -1})
+		g := NewGame([]Marker{{}}, letterGoals("AB", nil))
+		x, y := g.Goals[0].X, g.Goals[0].Y
+		g.advance()
+		g.advance()
+		moved := g.Goals[0].X != x || g.Goals[0].Y != y
+		if g.CurGoal != tt.wantGoal || g.Won != tt.wantWon || moved != tt.wantMoved {
+			t.Errorf("%s: goal %d won %v moved %v, want %d %v %v", tt.policy, g.CurGoal, g.Won, moved, tt.wantGoal, tt.wantWon, tt.wantMoved)
+		}
+	}
+}
+
+func TestRumbleCollector(t *testing.T) {
+	useConfig(t, Config{CollectButton: -1})
+	g := NewGame([]Marker{{X: -500, Y: -500}, {}}, letterGoals("AB", nil))
+	goal := g.Goals[0]
+	g.Markers[1].X, g.Markers[1].Y = goal.X, goal.Y
+	var rumbled []int
+	g.OnRumble = func(player int) {
+		rumbled = append(rumbled, player)
+	}
+	g.Update()
+	if len(rumbled) != 1 || rumbled[0] != 1 {
+		t.Errorf("rumbled %v, want player 1", rumbled)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/ttf"
+	"math/rand"
+	"strconv"
+)
+
+// Built in sets of goals
+const (
+	GOALS_LETTERS = "letters" // the alphabet
+	GOALS_NUMBERS = "numbers" // counting from 1 to NUMBERS_MAX
+	GOALS_SHAPES  = "shapes"  // colored shapes, see shapes.go
+)
+
+// the last number in the numbers goal set
+const NUMBERS_MAX = 20
+
+// buildGoalSet builds the goals of a built in goal set, placed at random
+func buildGoalSet(f *ttf.Font, set string, rng *rand.Rand) []*Goal {
+	var goals []*Goal
+	if set == GOALS_SHAPES {
+		goals = shapeGoals(rng)
+	} else {
+		goals = buildTextGoals(f, goalTexts(set), rng)
+	}
+	if n := config.GoalCount; n > 0 && n < len(goals) {
+		for _, goal := range goals[n:] {
+			goal.Free()
+		}
+		goals = goals[:n]
+	}
+	return goals
+}
+
+// goalTexts gives the text of each goal in a built in goal set, in the order they are collected
+func goalTexts(set string) []string {
+	var texts []string
+	switch set {
+	case GOALS_NUMBERS:
+		for i := 1; i <= NUMBERS_MAX; i++ {
+			texts = append(texts, strconv.Itoa(i))
+		}
+	default:
+		for _, ch := range GOALS_SRC {
+			texts = append(texts, string(ch))
+		}
+	}
+	return texts
+}
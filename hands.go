@@ -0,0 +1,45 @@
+package main
+
+// addHands gives each player a second marker for their right stick, so one child can practice using
+// both hands on a single controller.  The second marker is a lighter shade of the player's color.
+func (g *Game) addHands() {
+	for i := len(g.Hands); i < len(g.Markers); i++ {
+		m := g.Markers[i]
+		hand := Marker{Color: lighter(m.Color), Pattern: m.Pattern, Bounds: m.Bounds, cache: &markerCache{}}
+		x, y, w, h := hand.Area()
+		// start a quarter of the way across, clear of the first marker
+		hand.X, hand.Y = x+w/4, y+h/2
+		g.Hands = append(g.Hands, hand)
+	}
+}
+
+// lighter mixes a color half way to white
+func lighter(c uint32) uint32 {
+	r, gr, b := (c>>16)&0xff, (c>>8)&0xff, c&0xff
+	return ((r+0xff)/2)<<16 | ((gr+0xff)/2)<<8 | (b+0xff)/2
+}
+
+// everyoneOn reports whether every player is on the goal at once, with either of their markers, and
+// able to collect it
+func (g *Game) everyoneOn(goal *Goal) bool {
+	r := goal.Rect()
+	for i := range g.Markers {
+		on := g.Markers[i].Intersects(r) && g.Markers[i].Settled(goal) ||
+			i < len(g.Hands) && g.Hands[i].Intersects(r) && g.Hands[i].Settled(goal)
+		if !on || !g.Markers[i].CanCollect() {
+			return false
+		}
+	}
+	return len(g.Markers) > 0
+}
+
+// handOn gives the player whose second marker is on the rectangle and able to collect, or -1
+func (g *Game) handOn(goal *Goal) int {
+	r := goal.Rect()
+	for i := range g.Hands {
+		if g.Hands[i].Intersects(r) && g.Hands[i].Settled(goal) && g.Markers[i].CanCollect() {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+)
+
+const (
+	// how far from the middle of the marker the arrow's tip is
+	HINT_RADIUS = 40
+	// how long and wide the arrow is
+	HINT_LENGTH = 16
+	HINT_WIDTH  = 14
+)
+
+// A HintArrow is a Drawable arrow next to a marker pointing the way to the current goal, for players
+// who lose track of where to go.  It is in the player's color so everybody can tell which is theirs.
+type HintArrow struct {
+	X, Y   int     // the middle of the marker
+	DX, DY float64 // which way the goal is, of length 1
+	Color  uint32
+}
+
+// Hint gives the arrow pointing m to the current goal, if config.Hint is on and the goal is further
+// than config.HintDistance away.  Races have a goal for each player and simon says would be given
+// away, so they get no hints, and neither does test mode.
+func (g *Game) Hint(m *Marker) (HintArrow, bool) {
+	goal := g.Current()
+	if !config.Hint || !g.AssistsEnabled() || goal == nil || g.Won || g.Finished || config.Mode == MODE_RACE || config.Mode == MODE_SIMON {
+		return HintArrow{}, false
+	}
+	dx, dy := goal.X-m.X, goal.Y-m.Y
+	if config.Edges != EDGE_WALL && config.Edges != EDGE_BOUNCE {
+		// the marker can go off one side to come back on the other, which may be shorter
+		_, _, w, h := m.Area()
+		dx, dy = nearestWrap(dx, w), nearestWrap(dy, h)
+	}
+	dist := math.Hypot(float64(dx), float64(dy))
+	if dist < config.HintDistance || dist == 0 {
+		return HintArrow{}, false
+	}
+	return HintArrow{X: m.X, Y: m.Y, DX: float64(dx) / dist, DY: float64(dy) / dist, Color: m.Color}, true
+}
+
+// nearestWrap gives the shortest way to go d when going size takes you back to the start
+func nearestWrap(d, size int) int {
+	d = wrap(d, size)
+	if d > size/2 {
+		d -= size
+	}
+	return d
+}
+
+// corners gives the tip and the two back corners of the arrow
+func (a HintArrow) corners() [3][2]float64 {
+	tx, ty := float64(a.X)+a.DX*HINT_RADIUS, float64(a.Y)+a.DY*HINT_RADIUS
+	bx, by := tx-a.DX*HINT_LENGTH, ty-a.DY*HINT_LENGTH
+	// across the arrow is the direction turned a quarter
+	px, py := -a.DY*HINT_WIDTH/2, a.DX*HINT_WIDTH/2
+	return [3][2]float64{{tx, ty}, {bx + px, by + py}, {bx - px, by - py}}
+}
+
+// Get the bounding rectangle of the arrow
+func (a HintArrow) Rect() *sdl.Rect {
+	c := a.corners()
+	x0, y0, x1, y1 := c[0][0], c[0][1], c[0][0], c[0][1]
+	for _, p := range c[1:] {
+		x0, y0 = math.Min(x0, p[0]), math.Min(y0, p[1])
+		x1, y1 = math.Max(x1, p[0]), math.Max(y1, p[1])
+	}
+	return &sdl.Rect{int16(x0), int16(y0), uint16(x1-x0) + 1, uint16(y1-y0) + 1}
+}
+
+// Draw the arrow, a triangle filled a row at a time
+func (a HintArrow) Draw(screen *sdl.Surface) {
+	c := a.corners()
+	r := a.Rect()
+	for y := int(r.Y); y < int(r.Y)+int(r.H); y++ {
+		fy := float64(y) + 0.5
+		// where the row crosses the triangle's edges
+		x0, x1 := math.Inf(1), math.Inf(-1)
+		for i := range c {
+			p, q := c[i], c[(i+1)%3]
+			if (p[1] <= fy) == (q[1] <= fy) {
+				continue
+			}
+			x := p[0] + (fy-p[1])*(q[0]-p[0])/(q[1]-p[1])
+			x0, x1 = math.Min(x0, x), math.Max(x1, x)
+		}
+		if x1 > x0 {
+			screen.FillRect(&sdl.Rect{int16(x0), int16(y), uint16(x1-x0) + 1, 1}, a.Color)
+		}
+	}
+}
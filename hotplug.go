@@ -0,0 +1,358 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+	"path/filepath"
+)
+
+// colors the players' markers are drawn in
+var markerColors = [3]uint32{uint32(0x00aa0000), uint32(0x00009900), uint32(0x00000099)}
+
+// Sticks keeps track of the open joysticks.  SDL 1.2 only looks for joysticks when its joystick
+// subsystem starts, so noticing a device being plugged in or out means restarting it.  That drops the
+// state of every stick, so it is only done when the joystick device nodes change.
+type Sticks struct {
+	Open     []*sdl.Joystick
+	Names    []string
+	Cal      Calibrations              // stick calibrations, by name
+	Maps     map[string]*ControllerMap // logical controls of sticks, by name
+	Actions  ButtonActions             // what the buttons of sticks do, by name
+	Skip     map[int]bool              // sticks that were not picked to play, by SDL index
+	Profiles map[string]*Profile       // how particular sticks are set up, by name
+	Drift    *Drift                    // where worn sticks rest
+	nodes    int
+}
+
+// OpenSticks opens all the joysticks SDL knows about
+func OpenSticks() *Sticks {
+	s := &Sticks{nodes: joystickNodes(), Cal: make(Calibrations), Skip: make(map[int]bool)}
+	if config.Evdev == "" {
+		s.open()
+	}
+	return s
+}
+
+func (s *Sticks) open() {
+	n := sdl.NumJoysticks()
+	s.Open = make([]*sdl.Joystick, n)
+	s.Names = make([]string, n)
+	fmt.Println("Found ", n, " joysticks:")
+	for i := 0; i < n; i++ {
+		s.Names[i] = sdl.JoystickName(i)
+		fmt.Println(i+1, " ", s.Names[i])
+		s.Open[i] = sdl.JoystickOpen(i)
+	}
+}
+
+// Used gives the sticks that are playing, in the order they are numbered for -bind
+func (s *Sticks) Used() []*sdl.Joystick {
+	var used []*sdl.Joystick
+	for i, j := range s.Open {
+		if s.playing(i) {
+			used = append(used, j)
+		}
+	}
+	return used
+}
+
+// playing reports whether the stick with SDL index which is one of the players' own, not one left out
+// or the -tilt device
+func (s *Sticks) playing(which int) bool {
+	return which >= 0 && which != config.TiltDevice && !s.Skip[which]
+}
+
+// Device gives the number of a playing stick from its SDL index.  Sticks that are not playing are left
+// out of the count, and give false.  Indexes past the open sticks (from -fake-input) count on from the
+// last one.
+func (s *Sticks) Device(which int) (int, bool) {
+	if !s.playing(which) {
+		return 0, false
+	}
+	dev := 0
+	for i := 0; i < which; i++ {
+		if s.playing(i) {
+			dev++
+		}
+	}
+	return dev, true
+}
+
+// MovePlayer gives the player the stick with SDL index which moves.  The -tilt device steers
+// config.TiltPlayer.
+func (s *Sticks) MovePlayer(which int) (int, bool) {
+	if config.TiltDevice >= 0 && which == config.TiltDevice {
+		return config.TiltPlayer, true
+	}
+	if dev, ok := s.Device(which); ok {
+		return config.Inputs.MovePlayer(dev)
+	}
+	return 0, false
+}
+
+// ButtonPlayer gives the player that gets the buttons of the stick with SDL index which
+func (s *Sticks) ButtonPlayer(which int) (int, bool) {
+	if dev, ok := s.Device(which); ok {
+		return config.Inputs.ButtonPlayer(dev)
+	}
+	return 0, false
+}
+
+// Normalize an axis value of device dev using its calibration, if it has one
+func (s *Sticks) Normalize(dev, axis int, v int16) int16 {
+	if dev < 0 || dev >= len(s.Names) {
+		return v
+	}
+	return s.Cal.Normalize(s.Names[dev], axis, v)
+}
+
+// controllerMap gives the mapping to logical controls for device dev, nil if it has none
+func (s *Sticks) controllerMap(dev int) *ControllerMap {
+	if dev < 0 || dev >= len(s.Names) {
+		return nil
+	}
+	return s.Maps[s.Names[dev]]
+}
+
+// MapAxis turns a raw axis of device dev into a logical one.  It returns false for axes the mapping
+// does not use.  Sticks without a mapping use -axis-roles, their first two axes are the left stick.
+func (s *Sticks) MapAxis(dev, axis int, v int16) (int, int16, bool) {
+	if cm := s.controllerMap(dev); cm != nil {
+		return cm.Axis(axis, v)
+	}
+	if role, ok := config.AxisRoles[axis]; ok {
+		return role, v, true
+	}
+	// without roles only the first two axes are known to be a stick, and the next two with -two-hands
+	if config.TwoHands && (axis == AXIS_RIGHTX || axis == AXIS_RIGHTY) {
+		return axis, v, true
+	}
+	return axis, v, axis == AXIS_LEFTX || axis == AXIS_LEFTY
+}
+
+// MapButton turns a raw button of device dev into a logical one.  It returns false for buttons the
+// mapping does not use.
+func (s *Sticks) MapButton(dev, button int) (int, bool) {
+	if cm := s.controllerMap(dev); cm != nil {
+		return cm.Button(button)
+	}
+	return button, true
+}
+
+// IsStart reports whether a raw button of device dev is its controller's Start button
+func (s *Sticks) IsStart(dev, button int) bool {
+	return s.IsButton(dev, button, BUTTON_START)
+}
+
+// IsButton reports whether a raw button of device dev is the logical button (one of the BUTTON_*
+// constants) on its controller.  Without a controller mapping there is no telling.
+func (s *Sticks) IsButton(dev, button, logical int) bool {
+	cm := s.controllerMap(dev)
+	if cm == nil {
+		return false
+	}
+	b, ok := cm.Button(button)
+	return ok && b == logical
+}
+
+// Action gives what a raw button of device dev does
+func (s *Sticks) Action(dev, button int) string {
+	if dev < 0 || dev >= len(s.Names) {
+		return ACTION_GROW
+	}
+	return s.Actions.Action(s.Names[dev], button)
+}
+
+// Pauses reports whether a raw button of device dev pauses the game
+func (s *Sticks) Pauses(dev, button int) bool {
+	return s.Action(dev, button) == ACTION_PAUSE || s.IsStart(dev, button)
+}
+
+// Input turns a joystick event into the input for the player it is routed to.  It returns false for
+// events that do nothing to the game.
+func (s *Sticks) Input(event interface{}) (Input, bool) {
+	switch e := event.(type) {
+	case sdl.JoyAxisEvent:
+		p, ok := s.MovePlayer(int(e.Which))
+		if !ok {
+			return Input{}, false
+		}
+		axis, value, ok := s.Axis(int(e.Which), int(e.Axis), e.Value)
+		return Input{Player: p, Kind: INPUT_AXIS, Index: axis, Value: value}, ok
+	case sdl.JoyHatEvent:
+		p, ok := s.MovePlayer(int(e.Which))
+		return Input{Player: p, Kind: INPUT_HAT, Index: int(e.Hat), Value: int16(e.Value)}, ok
+	case sdl.JoyButtonEvent:
+		p, ok := s.ButtonPlayer(int(e.Which))
+		if !ok {
+			return Input{}, false
+		}
+		if s.Pauses(int(e.Which), int(e.Button)) {
+			return gameInput(GAME_PAUSE, 0), e.State > 0
+		}
+		switch s.Action(int(e.Which), int(e.Button)) {
+		case ACTION_GROW:
+			button, ok := s.MapButton(int(e.Which), int(e.Button))
+			return Input{Player: p, Kind: INPUT_BUTTON, Index: button, Value: int16(e.State)}, ok
+		case ACTION_COLLECT:
+			return Input{Player: p, Kind: INPUT_COLLECT, Value: int16(e.State)}, true
+		}
+	}
+	return Input{}, false
+}
+
+// Changed reports whether the joysticks may have been plugged in or out since the last check
+func (s *Sticks) Changed() bool {
+	if config.Evdev != "" {
+		// SDL is not handling the joysticks
+		return false
+	}
+	nodes := joystickNodes()
+	changed := nodes != s.nodes
+	s.nodes = nodes
+	return changed
+}
+
+// Rescan restarts SDL's joystick subsystem and opens the joysticks it finds.  A stick left out of
+// playing stays out if the same kind of stick is still at its index.
+func (s *Sticks) Rescan() {
+	names := s.Names
+	s.Close()
+	sdl.QuitSubSystem(sdl.INIT_JOYSTICK)
+	if sdl.InitSubSystem(sdl.INIT_JOYSTICK) != 0 {
+		fmt.Println(sdl.GetError())
+		return
+	}
+	s.open()
+	for i := range s.Skip {
+		if i >= len(names) || i >= len(s.Names) || s.Names[i] != names[i] {
+			delete(s.Skip, i)
+		}
+	}
+}
+
+// Close all the joysticks
+func (s *Sticks) Close() {
+	for _, j := range s.Open {
+		if j != nil {
+			j.Close()
+		}
+	}
+	s.Open = nil
+	s.Names = nil
+}
+
+// joystickNodes counts the joystick device nodes, a cheap way to notice devices coming and going.  It is
+// always 0 on systems without them.
+func joystickNodes() int {
+	nodes, _ := filepath.Glob("/dev/input/js*")
+	return len(nodes)
+}
+
+// Create the marker for player i of n.  Players with their own area start in the middle of it, players
+// sharing the screen start spread around a circle so they do not sit on top of each other.
+func newMarker(i, n int) Marker {
+	m := Marker{Color: playerColor(i), Pattern: i, Bounds: playerBounds(i, n), cache: &markerCache{}}
+	x, y, w, h := m.Area()
+	m.X, m.Y = x+w/2, y+h/2
+	if m.Bounds.W == 0 && n > 1 {
+		angle := 2 * math.Pi * float64(i) / float64(n)
+		m.X += int(float64(h/4) * math.Sin(angle))
+		m.Y -= int(float64(h/4) * math.Cos(angle))
+	}
+	return m
+}
+
+// playerColor gives the color of player i.  The first players get the theme's colors or those of
+// config.Palette, after that the hues are spread out by the golden angle so every player looks
+// different.
+func playerColor(i int) uint32 {
+	if i < len(config.MarkerColors) {
+		return config.MarkerColors[i]
+	}
+	if palette := palettes[config.Palette]; i < len(palette) {
+		return palette[i]
+	}
+	hue := math.Mod(float64(i)*137.508, 360)
+	return hsv(hue, 0.8, 0.75)
+}
+
+// hsv converts a color from hue (in degrees), saturation and value to RGB
+func hsv(h, s, v float64) uint32 {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = c, x
+	case h < 120:
+		r, g = x, c
+	case h < 180:
+		g, b = c, x
+	case h < 240:
+		g, b = x, c
+	case h < 300:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := v - c
+	return uint32((r+m)*255)<<16 | uint32((g+m)*255)<<8 | uint32((b+m)*255)
+}
+
+// ConnectSticks hands the joysticks to the markers, adding a player for each new joystick, and making
+// sure there are at least minPlayers.  Players whose joystick went away keep their place and score but
+// stop moving until it comes back.  Players on the keyboard, mouse, a phone or MIDI are left alone, the
+// keys and buttons they hold are still held.
+func (g *Game) ConnectSticks(sticks []*sdl.Joystick, minPlayers int) {
+	n := config.Inputs.Players(len(sticks))
+	if n < minPlayers {
+		n = minPlayers
+	}
+	for i := len(g.Markers); i < n; i++ {
+		m := newMarker(i, n)
+		if i < len(config.Sprites) && g.Sprite != nil {
+			m.Sprite = g.Sprite(config.Sprites[i])
+		}
+		g.Markers = append(g.Markers, m)
+		g.event(fmt.Sprintf("join %d", i))
+	}
+	onStick := make([]bool, len(g.Markers))
+	for i := range g.Markers {
+		m := &g.Markers[i]
+		m.Bounds = playerBounds(i, len(g.Markers))
+		had := m.Joystick != nil
+		m.Joystick = nil
+		if i < len(sticks) {
+			m.Joystick = sticks[i]
+		}
+		if onStick[i] = had || m.Joystick != nil; onStick[i] {
+			// the sticks were all reopened, so nothing on them is held any more
+			m.Pause()
+		}
+		if had && m.Joystick == nil {
+			g.event(fmt.Sprintf("leave %d", i))
+		}
+	}
+	if config.TwoHands {
+		g.addHands()
+		for i := range g.Hands {
+			if i < len(onStick) && onStick[i] {
+				g.Hands[i].Pause()
+			}
+		}
+	}
+	g.enterMaze()
+}
+
+// Pause stops the marker, forgetting any held directions and buttons
+func (m *Marker) Pause() {
+	m.Vax, m.Vay, m.Vhx, m.Vhy = 0, 0, 0, 0
+	m.velX, m.velY = 0, 0
+	m.fracX, m.fracY = 0, 0
+	m.targetX, m.targetY = 0, 0
+	m.Triggers = [2]float32{}
+	m.Big, m.Held = 0, 0
+	m.buttons = 0
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"testing"
+)
+
+func TestTiltDevice(t *testing.T) {
+	useConfig(t, Config{TiltDevice: 1, TiltPlayer: 0})
+	s := &Sticks{Open: make([]*sdl.Joystick, 3), Names: []string{"Pad", "Pad Motion Sensors", "Pad"}}
+	tests := []struct {
+		which          int
+		move, buttons  int
+		moves, presses bool
+	}{
+		{0, 0, 0, true, true},
+		// the tilt device steers its player and has no marker of its own
+		{1, 0, 0, true, false},
+		{2, 1, 1, true, true},
+	}
+	for _, tt := range tests {
+		move, moves := s.MovePlayer(tt.which)
+		buttons, presses := s.ButtonPlayer(tt.which)
+		if moves != tt.moves || (moves && move != tt.move) {
+			t.Errorf("stick %d moves player %d %v, want %d %v", tt.which, move, moves, tt.move, tt.moves)
+		}
+		if presses != tt.presses || (presses && buttons != tt.buttons) {
+			t.Errorf("stick %d presses for player %d %v, want %d %v", tt.which, buttons, presses, tt.buttons, tt.presses)
+		}
+	}
+	if n := len(s.Used()); n != 2 {
+		t.Errorf("%d sticks used, want 2", n)
+	}
+}
+
+func TestSkipSameName(t *testing.T) {
+	useConfig(t, Config{TiltDevice: -1})
+	// two of the same pad, and the first is left out
+	s := &Sticks{Open: make([]*sdl.Joystick, 3), Names: []string{"Pad", "Pad", "Stick"}, Skip: map[int]bool{0: true}}
+	tests := []struct {
+		which   int
+		wantDev int
+		wantOK  bool
+	}{
+		{0, 0, false},
+		{1, 0, true},
+		{2, 1, true},
+	}
+	for _, tt := range tests {
+		if dev, ok := s.Device(tt.which); dev != tt.wantDev || ok != tt.wantOK {
+			t.Errorf("Device(%d) = %d %v, want %d %v", tt.which, dev, ok, tt.wantDev, tt.wantOK)
+		}
+	}
+	if n := len(s.Used()); n != 2 {
+		t.Errorf("%d sticks used, want 2", n)
+	}
+}
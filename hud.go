@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"strings"
+	"time"
+)
+
+const (
+	// points for collecting a goal, outside whack-a-mole
+	GOAL_POINTS = 10
+	// the most extra points for collecting a goal quickly
+	SPEED_BONUS = 10
+	// the speed bonus runs out after this long
+	BONUS_TIME = 10 * time.Second
+	// height of the HUD strip
+	HUD_HEIGHT = 30
+)
+
+// goalPoints gives the points for collecting a goal elapsed after it appeared, the faster the more
+func goalPoints(elapsed time.Duration) int {
+	if elapsed >= BONUS_TIME {
+		return GOAL_POINTS
+	}
+	return GOAL_POINTS + int(SPEED_BONUS*float64(BONUS_TIME-elapsed)/float64(BONUS_TIME))
+}
+
+// A HUD is a strip along the bottom of the screen with the players' scores, the goal to collect, how
+// far through the goals they are and, on the right, how long the game has been going.
+type HUD struct {
+	label *Label
+	clock *Label
+}
+
+// Create a HUD drawn with font
+func NewHUD(font *ttf.Font) *HUD {
+	return &HUD{
+		label: &Label{Font: font, Color: config.TextColor, X: 10},
+		clock: &Label{Font: font, Color: config.TextColor},
+	}
+}
+
+// Update the HUD from the game
+func (h *HUD) Update(g *Game) {
+	switch config.Mode {
+	case MODE_RACE:
+		h.label.SetText(raceText(g))
+	case MODE_SIMON:
+		h.label.SetText(simonText(g))
+	case MODE_TRACK:
+		h.label.SetText(trackText(g))
+	default:
+		h.label.SetText(scoreText(g))
+	}
+	h.label.Y = frameH - HUD_HEIGHT + (HUD_HEIGHT-int(h.label.Rect().H))/2
+	h.clock.SetText(clockText(g.Clock))
+	h.clock.X = frameW - int(h.clock.Rect().W) - 10
+	h.clock.Y = h.label.Y
+}
+
+// clockText gives the time played as minutes and seconds
+func clockText(t time.Duration) string {
+	secs := int(t / time.Second)
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// scoreText gives the players' scores, the goal to collect and how far through the goals they are
+func scoreText(g *Game) string {
+	var parts []string
+	for i := range g.Markers {
+		parts = append(parts, fmt.Sprintf("P%d: %d", i+1, g.Markers[i].Score))
+	}
+	if len(config.Words) > 0 {
+		parts = append(parts, "word: "+config.Words[g.Word])
+	}
+	if goal := g.Current(); goal != nil && config.Mode != MODE_FREE {
+		parts = append(parts, "next: "+goal.Text)
+	}
+	if config.Mode == MODE_WHACK {
+		parts = append(parts, fmt.Sprintf("hits: %d  misses: %d", g.Collected, g.Misses))
+	} else {
+		parts = append(parts, fmt.Sprintf("%d/%d  left: %d", g.CurGoal, len(g.Goals), g.Remaining()))
+	}
+	return strings.Join(parts, "    ")
+}
+
+// raceText gives how far each player is through the race and who is ahead
+func raceText(g *Game) string {
+	var parts []string
+	for i := range g.Lanes {
+		parts = append(parts, fmt.Sprintf("P%d: %d/%d", i+1, g.Lanes[i].At, len(g.Lanes[i].Goals)))
+	}
+	if leader := g.Leader(); leader >= 0 {
+		parts = append(parts, fmt.Sprintf("P%d is ahead", leader+1))
+	} else {
+		parts = append(parts, "level")
+	}
+	return strings.Join(parts, "    ")
+}
+
+// Draw the HUD
+func (h *HUD) Draw(screen *sdl.Surface) {
+	screen.FillRect(h.Rect(), 0x00000000)
+	h.label.Draw(screen)
+	h.clock.Draw(screen)
+}
+
+// Get the strip the HUD covers
+func (h *HUD) Rect() *sdl.Rect {
+	return &sdl.Rect{0, int16(frameH - HUD_HEIGHT), uint16(frameW), HUD_HEIGHT}
+}
+
+// Free the HUD's text
+func (h *HUD) Free() {
+	h.label.Free()
+	h.clock.Free()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"time"
+)
+
+// isInput reports whether the event came from a player or teacher, as opposed to the window system
+func isInput(event interface{}) bool {
+	switch event.(type) {
+	case sdl.KeyboardEvent, sdl.MouseButtonEvent, sdl.MouseMotionEvent,
+		sdl.JoyAxisEvent, sdl.JoyButtonEvent, sdl.JoyHatEvent, sdl.JoyBallEvent:
+		return true
+	}
+	return false
+}
+
+// idleExpired reports whether there has been no input for longer than timeout.  A timeout of 0 never
+// expires.
+func idleExpired(lastInput, now time.Time, timeout time.Duration) bool {
+	return timeout > 0 && now.Sub(lastInput) > timeout
+}
+
+// GoIdle pauses the game because nobody is playing
+func (g *Game) GoIdle() {
+	g.Idle = true
+}
+
+// Wake starts a fresh round after the game has been idle
+func (g *Game) Wake() {
+	if !g.Idle {
+		return
+	}
+	g.Idle = false
+	g.Restart()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleExpired(t *testing.T) {
+	start := time.Unix(1000, 0)
+	tests := []struct {
+		after   time.Duration
+		timeout time.Duration
+		want    bool
+	}{
+		{time.Hour, 0, false},
+		{time.Second, time.Minute, false},
+		{time.Minute, time.Minute, false},
+		{time.Minute + time.Second, time.Minute, true},
+	}
+	for _, tt := range tests {
+		if got := idleExpired(start, start.Add(tt.after), tt.timeout); got != tt.want {
+			t.Errorf("idleExpired after %v with timeout %v = %v, want %v", tt.after, tt.timeout, got, tt.want)
+		}
+	}
+}
+
+func TestWakeRestarts(t *testing.T) {
+	useConfig(t, Config{CollectButton: -1})
+	g := NewGame([]Marker{{}}, letterGoals("ABC", nil))
+	g.advance()
+	g.Wake()
+	if g.CurGoal != 1 {
+		t.Errorf("waking a game that is not idle restarted it")
+	}
+	g.GoIdle()
+	g.Wake()
+	if g.Idle || g.CurGoal != 0 {
+		t.Errorf("waking left idle %v at goal %d, want a fresh round", g.Idle, g.CurGoal)
+	}
+}
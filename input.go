@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"strconv"
+	"strings"
+)
+
+// An InputMap routes joystick events to players.  Movement (axes and hats) and buttons are routed
+// separately, so a player can move with one device and press buttons on another, for example a big
+// accessibility button that shows up as its own joystick.  Devices that are not mentioned control the
+// player with the same index.  A device that is mentioned only sends what it is bound for.
+type InputMap struct {
+	move    map[int]int
+	buttons map[int]int
+}
+
+// parseInputMap reads bindings of the form "dev=player", "dev.move=player" or "dev.buttons=player",
+// separated by commas.  Devices and players are counted from 0.
+func parseInputMap(s string) (InputMap, error) {
+	im := InputMap{move: make(map[int]int), buttons: make(map[int]int)}
+	for _, b := range strings.Split(s, ",") {
+		if b = strings.TrimSpace(b); b == "" {
+			continue
+		}
+		parts := strings.SplitN(b, "=", 2)
+		if len(parts) != 2 {
+			return im, fmt.Errorf("bad binding %q, expected dev=player", b)
+		}
+		src := strings.SplitN(parts[0], ".", 2)
+		dev, err := strconv.Atoi(src[0])
+		if err != nil || dev < 0 {
+			return im, fmt.Errorf("bad device in binding %q", b)
+		}
+		player, err := strconv.Atoi(parts[1])
+		if err != nil || player < 0 {
+			return im, fmt.Errorf("bad player in binding %q", b)
+		}
+		kind := "all"
+		if len(src) == 2 {
+			kind = src[1]
+		}
+		switch kind {
+		case "all":
+			im.move[dev] = player
+			im.buttons[dev] = player
+		case "move":
+			im.move[dev] = player
+		case "buttons":
+			im.buttons[dev] = player
+		default:
+			return im, fmt.Errorf("bad input %q in binding %q, expected move or buttons", kind, b)
+		}
+	}
+	return im, nil
+}
+
+// bound reports whether the device has any bindings
+func (im InputMap) bound(dev int) bool {
+	_, m := im.move[dev]
+	_, b := im.buttons[dev]
+	return m || b
+}
+
+// MovePlayer gives the player that device dev moves
+func (im InputMap) MovePlayer(dev int) (int, bool) {
+	if !im.bound(dev) {
+		return dev, true
+	}
+	p, ok := im.move[dev]
+	return p, ok
+}
+
+// ButtonPlayer gives the player that receives the buttons of device dev
+func (im InputMap) ButtonPlayer(dev int) (int, bool) {
+	if !im.bound(dev) {
+		return dev, true
+	}
+	p, ok := im.buttons[dev]
+	return p, ok
+}
+
+// Players gives how many players there are with devices devices connected
+func (im InputMap) Players(devices int) int {
+	n := 0
+	for dev := 0; dev < devices; dev++ {
+		for _, p := range []func(int) (int, bool){im.MovePlayer, im.ButtonPlayer} {
+			if player, ok := p(dev); ok && player+1 > n {
+				n = player + 1
+			}
+		}
+	}
+	return n
+}
+
+// Axis handles the movement of a stick axis
+func (m *Marker) Axis(axis int, value int16) {
+	// the deadzone was already taken off when the input was routed
+	val := float32(value) / float32(uint32(0x0ffff))
+	//fmt.Println("got joystick axis event ", e)
+
+	switch axis {
+	case AXIS_LEFTX:
+		m.targetX = val
+		if config.Smoothing == 0 {
+			m.Vax = val
+		}
+	case AXIS_LEFTY:
+		m.targetY = val
+		if config.Smoothing == 0 {
+			m.Vay = val
+		}
+	case AXIS_TRIGGERLEFT, AXIS_TRIGGERRIGHT:
+		// a released trigger is at 0, or below it for triggers that have not been calibrated
+		t := float32(value) / 32767
+		if t < 0 {
+			t = 0
+		}
+		m.Triggers[axis-AXIS_TRIGGERLEFT] = t
+	}
+}
+
+// Get how much faster the triggers make the marker go, 1 for not at all
+func (m *Marker) Boost() float32 {
+	t := m.Triggers[0]
+	if m.Triggers[1] > t {
+		t = m.Triggers[1]
+	}
+	return 1 + t*float32(config.TriggerBoost-1)
+}
+
+// MoveTo puts the marker at a world position along one axis, 0 for x and 1 for y
+func (m *Marker) MoveTo(axis int, pos int) {
+	if m.Walls != nil {
+		// no jumping through walls, slide there instead
+		if axis == 0 {
+			m.moveBy(pos-m.X, 0)
+		} else {
+			m.moveBy(0, pos-m.Y)
+		}
+		return
+	}
+	if axis == 0 {
+		m.X = pos
+	} else {
+		m.Y = pos
+	}
+	m.keepInBounds()
+}
+
+// positionInputs gives the inputs that put a player at a world position
+func positionInputs(player, x, y int) []Input {
+	return []Input{
+		{Player: player, Kind: INPUT_POSITION, Index: 0, Value: int16(x)},
+		{Player: player, Kind: INPUT_POSITION, Index: 1, Value: int16(y)},
+	}
+}
+
+// Button handles a button being pressed or released
+func (m *Marker) Button(button int, pressed bool) {
+	m.SetButton(button, pressed)
+	if button == config.CollectButton {
+		m.Collect(pressed)
+		return
+	}
+	if pressed {
+		m.Big++
+		m.Held++
+		m.Presses++
+	} else {
+		m.Big--
+		m.Held--
+	}
+	if m.Big < 0 {
+		m.Big = 0
+	}
+	if m.Held < 0 {
+		m.Held = 0
+	}
+}
+
+// Hat handles the hat moving to a new position
+func (m *Marker) Hat(value uint8) {
+	m.Vhx, m.Vhy = hatDirection(value)
+}
+
+// hatDirection gives the direction a hat position points in
+func hatDirection(value uint8) (x, y float32) {
+	switch value {
+	case sdl.HAT_UP:
+		return 0.0, -1.0
+	case sdl.HAT_RIGHT:
+		return 1.0, 0.0
+	case sdl.HAT_DOWN:
+		return 0.0, 1.0
+	case sdl.HAT_LEFT:
+		return -1.0, 0.0
+	case sdl.HAT_RIGHTUP:
+		return 1.0, -1.0
+	case sdl.HAT_RIGHTDOWN:
+		return 1.0, 1.0
+	case sdl.HAT_LEFTUP:
+		return -1.0, -1.0
+	case sdl.HAT_LEFTDOWN:
+		return -1.0, 1.0
+	}
+	return 0.0, 0.0
+}
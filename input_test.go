@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+)
+
+// a button going down or up
+type press struct {
+	button int
+	down   bool
+}
+
+func TestPressCounter(t *testing.T) {
+	useConfig(t, Config{CollectButton: -1})
+	tests := []struct {
+		name        string
+		presses     []press
+		wantPresses int
+		wantHeld    int
+	}{
+		{"nothing", nil, 0, 0},
+		{"press", []press{{0, true}}, 1, 1},
+		{"press and release", []press{{0, true}, {0, false}}, 1, 0},
+		{"two held", []press{{0, true}, {1, true}}, 2, 2},
+		{"release does not count", []press{{0, true}, {0, false}, {0, true}, {0, false}}, 2, 0},
+		{"stray release", []press{{3, false}}, 0, 0},
+	}
+	for _, tt := range tests {
+		var m Marker
+		for _, p := range tt.presses {
+			m.Button(p.button, p.down)
+		}
+		if m.Presses != tt.wantPresses || m.Held != tt.wantHeld {
+			t.Errorf("%s: presses %d held %d, want %d and %d", tt.name, m.Presses, m.Held, tt.wantPresses, tt.wantHeld)
+		}
+	}
+}
+
+func TestPressCounterResetsEachRound(t *testing.T) {
+	useConfig(t, Config{CollectButton: -1})
+	g := NewGame([]Marker{{}}, nil)
+	g.Markers[0].Button(0, true)
+	g.Markers[0].Button(0, false)
+	g.newRound()
+	if g.Markers[0].Presses != 0 {
+		t.Errorf("presses = %d after a new round, want 0", g.Markers[0].Presses)
+	}
+}
+
+func TestInputMap(t *testing.T) {
+	im, err := parseInputMap("1.move=0, 2.buttons=0, 3=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		dev            int
+		move, buttons  int
+		moves, presses bool
+	}{
+		{0, 0, 0, true, true},
+		{1, 0, 0, true, false},
+		{2, 0, 0, false, true},
+		{3, 1, 1, true, true},
+		{4, 4, 4, true, true},
+	}
+	for _, tt := range tests {
+		move, moves := im.MovePlayer(tt.dev)
+		buttons, presses := im.ButtonPlayer(tt.dev)
+		if moves != tt.moves || (moves && move != tt.move) {
+			t.Errorf("device %d moves player %d %v, want %d %v", tt.dev, move, moves, tt.move, tt.moves)
+		}
+		if presses != tt.presses || (presses && buttons != tt.buttons) {
+			t.Errorf("device %d presses for player %d %v, want %d %v", tt.dev, buttons, presses, tt.buttons, tt.presses)
+		}
+	}
+	if n := im.Players(4); n != 2 {
+		t.Errorf("Players(4) = %d, want 2", n)
+	}
+}
+
+func TestParseInputMapErrors(t *testing.T) {
+	for _, s := range []string{"1", "x=0", "1=x", "-1=0", "1=-1", "1.feet=0"} {
+		if _, err := parseInputMap(s); err == nil {
+			t.Errorf("parseInputMap(%q) did not fail", s)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		name     string
+		prevX, x int
+		alpha    float64
+		wantX    int
+	}{
+		{"start", 100, 120, 0, 100},
+		{"middle", 100, 120, 0.5, 110},
+		{"end", 100, 120, 1, 120},
+		{"backwards", 120, 100, 0.25, 115},
+		{"still", 100, 100, 0.5, 100},
+		{"wrapped is not smoothed", WIDTH - 2, 2, 0.5, 2},
+	}
+	for _, tt := range tests {
+		m := Marker{PrevX: tt.prevX, X: tt.x, PrevY: 300, Y: 300}
+		if got := m.Interpolate(tt.alpha); got.X != tt.wantX || got.Y != 300 {
+			t.Errorf("%s: drawn at %d,%d, want %d,300", tt.name, got.X, got.Y, tt.wantX)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Inversions lists the stick axes that point the wrong way, by device and raw axis
+type Inversions map[[2]int]bool
+
+// parseInversions reads a comma separated list of "dev.axis"
+func parseInversions(s string) (Inversions, error) {
+	inv := make(Inversions)
+	for _, a := range strings.Split(s, ",") {
+		if a = strings.TrimSpace(a); a == "" {
+			continue
+		}
+		parts := strings.SplitN(a, ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("bad axis %q to invert, expected dev.axis", a)
+		}
+		dev, err := strconv.Atoi(parts[0])
+		if err != nil || dev < 0 {
+			return nil, fmt.Errorf("bad device in axis %q to invert", a)
+		}
+		axis, err := strconv.Atoi(parts[1])
+		if err != nil || axis < 0 {
+			return nil, fmt.Errorf("bad axis in axis %q to invert", a)
+		}
+		inv[[2]int{dev, axis}] = true
+	}
+	return inv, nil
+}
+
+// Apply gives the axis value turned around if the axis is inverted
+func (inv Inversions) Apply(dev, axis int, v int16) int16 {
+	if inv[[2]int{dev, axis}] {
+		return invertAxis(v)
+	}
+	return v
+}
+
+// invertAxis turns an axis value around, keeping the center at 0.  -32768 has no opposite in an
+// int16, so it becomes 32767.
+func invertAxis(v int16) int16 {
+	if v == -32768 {
+		return 32767
+	}
+	return -v
+}
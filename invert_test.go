@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestInvertAxis(t *testing.T) {
+	tests := []struct {
+		v, want int16
+	}{
+		{0, 0},
+		{1, -1},
+		{-1, 1},
+		{32767, -32767},
+		{-32768, 32767},
+	}
+	for _, tt := range tests {
+		if got := invertAxis(tt.v); got != tt.want {
+			t.Errorf("invertAxis(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestInvertedRestingStickIsZero(t *testing.T) {
+	inv, err := parseInversions("0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	useConfig(t, Config{Deadzones: Deadzones{Default: 2000}, Invert: inv, FlipY: true})
+	var sticks Sticks
+	if _, v, ok := sticks.Axis(0, AXIS_LEFTY, 500); !ok || v != 0 {
+		t.Errorf("a resting inverted stick reads %d, want 0", v)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// A Keyboard drives a player like a joystick: the arrow keys or WASD move and space is button 0.  It
+// is for machines without a joystick.
+type Keyboard struct {
+	Player                int // the player it controls, -1 for none
+	left, right, up, down bool
+}
+
+// Key handles a key going down or up.  It returns the input the key causes, if any.
+func (k *Keyboard) Key(sym uint32, pressed bool) (Input, bool) {
+	if k.Player < 0 {
+		return Input{}, false
+	}
+	switch sym {
+	case sdl.K_LEFT, sdl.K_a:
+		k.left = pressed
+		return Input{Player: k.Player, Kind: INPUT_AXIS, Index: 0, Value: keyAxis(k.left, k.right)}, true
+	case sdl.K_RIGHT, sdl.K_d:
+		k.right = pressed
+		return Input{Player: k.Player, Kind: INPUT_AXIS, Index: 0, Value: keyAxis(k.left, k.right)}, true
+	case sdl.K_UP, sdl.K_w:
+		k.up = pressed
+		return Input{Player: k.Player, Kind: INPUT_AXIS, Index: 1, Value: keyAxis(k.up, k.down)}, true
+	case sdl.K_DOWN, sdl.K_s:
+		k.down = pressed
+		return Input{Player: k.Player, Kind: INPUT_AXIS, Index: 1, Value: keyAxis(k.up, k.down)}, true
+	case sdl.K_SPACE:
+		var v int16
+		if pressed {
+			v = 1
+		}
+		return Input{Player: k.Player, Kind: INPUT_BUTTON, Index: 0, Value: v}, true
+	}
+	return Input{}, false
+}
+
+// minPlayers gives how many players the keyboard, mouse, touchscreen, phones and MIDI need
+func minPlayers(k *Keyboard) int {
+	n := k.Player + 1
+	if config.MousePlayer+1 > n {
+		n = config.MousePlayer + 1
+	}
+	if config.TouchPlayer+1 > n {
+		n = config.TouchPlayer + 1
+	}
+	if config.PhoneAddr != "" && config.PhonePlayer+1 > n {
+		n = config.PhonePlayer + 1
+	}
+	if config.MidiPath != "" && config.MidiPlayer+1 > n {
+		n = config.MidiPlayer + 1
+	}
+	if config.TiltDevice >= 0 && config.TiltPlayer+1 > n {
+		n = config.TiltPlayer + 1
+	}
+	return n
+}
+
+// keyAxis gives the axis position for a pair of opposite keys, a full push if only one is held
+func keyAxis(neg, pos bool) int16 {
+	switch {
+	case neg && !pos:
+		return -32768
+	case pos && !neg:
+		return 32767
+	}
+	return 0
+}
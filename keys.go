@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"strings"
+)
+
+// one past the highest SDL key symbol
+const KEY_LAST = 323
+
+// the keys the game itself uses, the teacher keys cannot be put on them
+var builtinKeys = map[uint32]string{
+	sdl.K_F1:     "switching high contrast",
+	sdl.K_F2:     "saving the layout",
+	sdl.K_F3:     "the layout editor",
+	sdl.K_F4:     "calibrating",
+	sdl.K_F8:     "test mode",
+	sdl.K_F9:     "remapping buttons",
+	sdl.K_F10:    "flipping up and down",
+	sdl.K_F11:    "showing the controllers",
+	sdl.K_F12:    "the debug overlay",
+	sdl.K_PRINT:  "screenshots",
+	sdl.K_PAUSE:  "pausing",
+	sdl.K_ESCAPE: "quitting",
+	sdl.K_q:      "quitting",
+}
+
+// lookupKey finds the key symbol SDL names name (e.g. "f5", "return", "a").  SDL must be initialised.
+func lookupKey(name string) (uint32, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return 0, false
+	}
+	for k := 1; k < KEY_LAST; k++ {
+		if sdl.GetKeyName(sdl.Key(k)) == name {
+			return uint32(k), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+)
+
+// A Label is a Drawable that shows a line of text with its top left corner at X, Y.
+// The text is only rendered again when it changes.
+type Label struct {
+	Font    *ttf.Font
+	Color   sdl.Color
+	X, Y    int
+	text    string
+	surface *sdl.Surface
+}
+
+// Set the text shown by the label
+func (l *Label) SetText(text string) {
+	if text == l.text && l.surface != nil {
+		return
+	}
+	l.Free()
+	l.text = text
+	if text != "" {
+		l.surface = ttf.RenderUTF8_Blended(l.Font, text, l.Color)
+	}
+}
+
+// Release the rendered text
+func (l *Label) Free() {
+	if l.surface != nil {
+		l.surface.Free()
+		l.surface = nil
+	}
+}
+
+// Get the bounding rectangle for the Label
+func (l *Label) Rect() *sdl.Rect {
+	if l.surface == nil {
+		return &sdl.Rect{int16(l.X), int16(l.Y), 0, 0}
+	}
+	return &sdl.Rect{int16(l.X), int16(l.Y), uint16(l.surface.W), uint16(l.surface.H)}
+}
+
+// Draw the label on the given surface
+func (l *Label) Draw(screen *sdl.Surface) {
+	if l.surface == nil {
+		return
+	}
+	screen.Blit(l.Rect(), l.surface, nil)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"os"
+	"sort"
+)
+
+// A LayoutGoal records the text, order and position of one goal
+type LayoutGoal struct {
+	Text  string
+	Order int
+	X, Y  int
+}
+
+// A Layout is a saved arrangement of goals that can be loaded instead of placing them at random
+type Layout struct {
+	Goals []LayoutGoal
+}
+
+// saveLayout writes the text, order and positions of the goals to the file at path
+func saveLayout(path string, goals []*Goal) error {
+	var l Layout
+	for _, g := range goals {
+		l.Goals = append(l.Goals, LayoutGoal{Text: g.Text, Order: g.Order, X: g.X, Y: g.Y})
+	}
+	data, err := json.MarshalIndent(&l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadLayout reads a layout saved by saveLayout.  The goals are returned sorted by their order.
+func loadLayout(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &Layout{}
+	if err = json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(l.Goals) == 0 {
+		return nil, fmt.Errorf("%s: layout has no goals", path)
+	}
+	sort.SliceStable(l.Goals, func(i, j int) bool { return l.Goals[i].Order < l.Goals[j].Order })
+	return l, nil
+}
+
+// layoutGoals builds the goals described by the layout.  Goals that would not be entirely on screen
+// are moved back onto it with a warning.
+func layoutGoals(f *ttf.Font, l *Layout) []*Goal {
+	goals := make([]*Goal, len(l.Goals))
+	for i, lg := range l.Goals {
+		g := NewGoal(f, lg.Text, i)
+		g.X, g.Y = clampGoal(g, lg.X, lg.Y)
+		if g.X != lg.X || g.Y != lg.Y {
+			fmt.Printf("layout: goal %q at %d,%d is off screen, moved to %d,%d\n", lg.Text, lg.X, lg.Y, g.X, g.Y)
+		}
+		goals[i] = g
+	}
+	return goals
+}
+
+// clampGoal returns the position closest to x, y where the goal is entirely on the playfield
+func clampGoal(g *Goal, x, y int) (int, int) {
+	w, h := worldSize()
+	return clamp(x, g.W/2, w-g.W/2), clamp(y, g.H/2, h-g.H/2)
+}
+
+// clamp v to the range [lo, hi]
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
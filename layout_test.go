@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLayoutRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.json")
+	goals := []*Goal{
+		{Text: "B", Order: 1, X: 300, Y: 200},
+		{Text: "A", Order: 0, X: 100, Y: 150},
+		{Text: "C", Order: 2, X: 700, Y: 600},
+	}
+	if err := saveLayout(path, goals); err != nil {
+		t.Fatal(err)
+	}
+	l, err := loadLayout(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LayoutGoal{{"A", 0, 100, 150}, {"B", 1, 300, 200}, {"C", 2, 700, 600}}
+	if !reflect.DeepEqual(l.Goals, want) {
+		t.Errorf("loaded %v, want %v", l.Goals, want)
+	}
+}
+
+func TestLoadLayoutErrors(t *testing.T) {
+	tests := []struct {
+		name, data string
+	}{
+		{"not json", "A at 100,100"},
+		{"no goals", `{"Goals": []}`},
+		{"wrong type", `{"Goals": [{"X": "left"}]}`},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "layout.json")
+		if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadLayout(path); err == nil {
+			t.Errorf("%s: loaded without an error", tt.name)
+		}
+	}
+	if _, err := loadLayout(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("a missing layout loaded without an error")
+	}
+}
+
+func TestClampGoal(t *testing.T) {
+	g := &Goal{W: 40, H: 60}
+	tests := []struct {
+		x, y         int
+		wantX, wantY int
+	}{
+		{100, 100, 100, 100},
+		{0, 0, 20, 30},
+		{WIDTH, HEIGHT, WIDTH - 20, HEIGHT - 30},
+		{-50, 400, 20, 400},
+	}
+	for _, tt := range tests {
+		if x, y := clampGoal(g, tt.x, tt.y); x != tt.wantX || y != tt.wantY {
+			t.Errorf("clampGoal(%d, %d) = %d,%d, want %d,%d", tt.x, tt.y, x, y, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestLayoutKeptOnNewRound(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		start  func(g *Game)
+		moved  bool
+	}{
+		{"next round", "", func(g *Game) { g.endOfSequence(END_NEXT) }, true},
+		{"restart", "", func(g *Game) { g.Restart() }, true},
+		{"next round with a layout", "layout.json", func(g *Game) { g.endOfSequence(END_NEXT) }, false},
+		{"restart with a layout", "layout.json", func(g *Game) { g.Restart() }, false},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{LayoutPath: tt.layout})
+		goal := &Goal{W: 40, H: 40, X: 123, Y: 456}
+		g := NewGame(nil, []*Goal{goal})
+		tt.start(g)
+		if moved := goal.X != 123 || goal.Y != 456; moved != tt.moved {
+			t.Errorf("%s: goal moved %v, want %v", tt.name, moved, tt.moved)
+		}
+	}
+}
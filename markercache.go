@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// markerLook is everything that decides how a marker's body is drawn
+type markerLook struct {
+	w, h     int
+	color    uint32
+	pattern  int
+	patterns bool
+	sprite   *sdl.Surface
+}
+
+// A markerCache keeps a marker's body drawn on a surface of its own, so it is only drawn again when
+// its size, color, pattern or sprite changes.  Markers are copied to be drawn, so they share the cache
+// through a pointer.
+type markerCache struct {
+	look    markerLook
+	surface *sdl.Surface
+}
+
+// get gives the marker's body drawn at the size of r, drawing it again if anything changed.  It gives
+// nil if the surface cannot be made.
+func (c *markerCache) get(m *Marker, r *sdl.Rect) *sdl.Surface {
+	if c == nil {
+		return nil
+	}
+	look := markerLook{int(r.W), int(r.H), m.Color, m.Pattern, config.Patterns, m.Sprite}
+	if c.surface != nil && look == c.look {
+		return c.surface
+	}
+	c.free()
+	c.look = look
+	if look.w < 1 || look.h < 1 {
+		return nil
+	}
+	if m.Sprite != nil {
+		c.surface = scaleSurface(m.Sprite, look.w, look.h)
+		return c.surface
+	}
+	c.surface = sdl.CreateRGBSurface(sdl.SWSURFACE, look.w, look.h, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0)
+	if c.surface == nil {
+		return nil
+	}
+	body := &sdl.Rect{0, 0, uint16(look.w), uint16(look.h)}
+	c.surface.FillRect(body, m.Color)
+	if config.Patterns {
+		drawPattern(c.surface, body, m.Pattern)
+	}
+	return c.surface
+}
+
+// free releases the drawn body
+func (c *markerCache) free() {
+	if c != nil && c.surface != nil {
+		c.surface.Free()
+		c.surface = nil
+	}
+}
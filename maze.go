@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// the color maze walls are drawn in
+const MAZE_WALL_COLOR = 0x004060a0
+
+// A Maze is a grid of walls read from a text file and stretched over the screen.  In the file each
+// character is a cell: # is a wall, @ is where a player starts, a letter or digit is a goal and
+// anything else is open floor.  Goals are collected in alphabetical order.
+type Maze struct {
+	Cols, Rows   int
+	CellW, CellH int
+	walls        []bool
+	starts       [][2]int // the middle of each start cell, in pixels
+	goals        map[string][2]int
+}
+
+// loadMaze reads a maze file
+func loadMaze(path string) (*Maze, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	mz, err := parseMaze(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return mz, nil
+}
+
+// parseMaze builds a maze from its text.  Short lines are padded with open floor.
+func parseMaze(text string) (*Maze, error) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	mz := &Maze{Rows: len(lines), goals: make(map[string][2]int)}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+		if n := len([]rune(lines[i])); n > mz.Cols {
+			mz.Cols = n
+		}
+	}
+	if mz.Cols == 0 {
+		return nil, fmt.Errorf("empty maze")
+	}
+	w, h := worldSize()
+	mz.CellW, mz.CellH = w/mz.Cols, h/mz.Rows
+	if mz.CellW < markerW || mz.CellH < markerH {
+		return nil, fmt.Errorf("maze of %dx%d cells is too big for the screen", mz.Cols, mz.Rows)
+	}
+	mz.walls = make([]bool, mz.Cols*mz.Rows)
+	for row, line := range lines {
+		for col, ch := range []rune(line) {
+			middle := [2]int{col*mz.CellW + mz.CellW/2, row*mz.CellH + mz.CellH/2}
+			switch {
+			case ch == '#':
+				mz.walls[row*mz.Cols+col] = true
+			case ch == '@':
+				mz.starts = append(mz.starts, middle)
+			case unicode.IsLetter(ch) || unicode.IsDigit(ch):
+				if _, dup := mz.goals[string(ch)]; dup {
+					return nil, fmt.Errorf("goal %c is in the maze twice", ch)
+				}
+				mz.goals[string(ch)] = middle
+			}
+		}
+	}
+	if len(mz.goals) == 0 {
+		return nil, fmt.Errorf("maze has no goals")
+	}
+	return mz, nil
+}
+
+// Goals builds the maze's goals in their cells
+func (mz *Maze) Goals(f *ttf.Font) []*Goal {
+	var texts []string
+	for text := range mz.goals {
+		texts = append(texts, text)
+	}
+	sort.Strings(texts)
+	goals := make([]*Goal, len(texts))
+	for i, text := range texts {
+		goals[i] = NewGoal(f, text, i)
+		goals[i].X, goals[i].Y = mz.goals[text][0], mz.goals[text][1]
+	}
+	return goals
+}
+
+// Start gives where player i starts, going round the start cells.  ok is false if there are none.
+func (mz *Maze) Start(i int) (x, y int, ok bool) {
+	if len(mz.starts) == 0 {
+		return 0, 0, false
+	}
+	s := mz.starts[i%len(mz.starts)]
+	return s[0], s[1], true
+}
+
+// wall reports whether the cell is a wall.  Everything outside the maze counts as wall.
+func (mz *Maze) wall(col, row int) bool {
+	if col < 0 || row < 0 || col >= mz.Cols || row >= mz.Rows {
+		return true
+	}
+	return mz.walls[row*mz.Cols+col]
+}
+
+// Blocked reports whether the rectangle overlaps any wall
+func (mz *Maze) Blocked(r *sdl.Rect) bool {
+	x0, y0 := int(r.X), int(r.Y)
+	x1, y1 := x0+int(r.W)-1, y0+int(r.H)-1
+	if x0 < 0 || y0 < 0 {
+		return true
+	}
+	for row := y0 / mz.CellH; row <= y1/mz.CellH; row++ {
+		for col := x0 / mz.CellW; col <= x1/mz.CellW; col++ {
+			if mz.wall(col, row) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Draw the walls
+func (mz *Maze) Draw(screen *sdl.Surface) {
+	for row := 0; row < mz.Rows; row++ {
+		for col := 0; col < mz.Cols; col++ {
+			if mz.walls[row*mz.Cols+col] {
+				screen.FillRect(&sdl.Rect{int16(col * mz.CellW), int16(row * mz.CellH), uint16(mz.CellW), uint16(mz.CellH)}, MAZE_WALL_COLOR)
+			}
+		}
+	}
+}
+
+// Get the area the maze covers
+func (mz *Maze) Rect() *sdl.Rect {
+	return &sdl.Rect{0, 0, uint16(mz.Cols * mz.CellW), uint16(mz.Rows * mz.CellH)}
+}
+
+// Body gives the part of the marker that cannot go through walls, offset by dx, dy.  Buttons making
+// the marker bigger do not change it, so growing cannot wedge the marker in a wall.
+func (m *Marker) Body(dx, dy int) *sdl.Rect {
+	return &sdl.Rect{int16(m.X + dx - markerW/2), int16(m.Y + dy - markerH/2), uint16(markerW), uint16(markerH)}
+}
+
+// moveBy moves the marker by dx, dy, stopping it against any walls.  The axes are moved one at a time
+// so the marker slides along a wall instead of sticking to it.
+func (m *Marker) moveBy(dx, dy int) {
+	if m.Walls == nil {
+		m.X += dx
+		m.Y += dy
+		return
+	}
+	for ; dx != 0 && !m.Walls.Blocked(m.Body(sign(dx), 0)); dx -= sign(dx) {
+		m.X += sign(dx)
+	}
+	for ; dy != 0 && !m.Walls.Blocked(m.Body(0, sign(dy))); dy -= sign(dy) {
+		m.Y += sign(dy)
+	}
+}
+
+// sign gives -1, 0 or 1 for the sign of v
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+// enterMaze puts the markers in the maze at their start cells
+func (g *Game) enterMaze() {
+	if g.Maze == nil {
+		return
+	}
+	for i := range g.Markers {
+		m := &g.Markers[i]
+		if m.Walls != g.Maze {
+			m.Walls = g.Maze
+			if x, y, ok := g.Maze.Start(i); ok {
+				m.X, m.Y = x, y
+				m.PrevX, m.PrevY = x, y
+			}
+		}
+	}
+	for i := range g.Hands {
+		// a second hand starts on top of the first, the only place known to be clear
+		h := &g.Hands[i]
+		if h.Walls != g.Maze {
+			h.Walls = g.Maze
+			h.X, h.Y = g.Markers[i].X, g.Markers[i].Y
+			h.PrevX, h.PrevY = h.X, h.Y
+		}
+	}
+}
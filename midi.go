@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MIDI reads a MIDI controller, such as a pad or fader box, from a raw MIDI device (/dev/snd/midiC1D0
+// on Linux, or /dev/midi1).  Control changes move the axes they are mapped to and notes press buttons,
+// both for config.MidiPlayer.
+type MIDI struct {
+	Inputs chan Input
+
+	f *os.File
+}
+
+// OpenMIDI starts reading the MIDI device at path
+func OpenMIDI(path string) (*MIDI, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &MIDI{Inputs: make(chan Input, 16), f: f}
+	go m.read(bufio.NewReader(f))
+	return m, nil
+}
+
+// read MIDI messages until the device goes away
+func (m *MIDI) read(r io.ByteReader) {
+	var status byte
+	var data []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			if err != io.EOF {
+				fmt.Println("midi:", err)
+			}
+			return
+		}
+		switch {
+		case c >= 0xf8:
+			// real time messages can come in the middle of anything
+			continue
+		case c >= 0x80:
+			status, data = c, data[:0]
+			continue
+		}
+		// a data byte, with running status the status is left off repeated messages
+		data = append(data, c)
+		if len(data) < 2 || status < 0x80 || status >= 0xf0 {
+			if status >= 0xc0 && status < 0xe0 {
+				// program and channel pressure changes have one data byte, skip them
+				data = data[:0]
+			}
+			continue
+		}
+		if in, ok := midiInput(status, data[0], data[1]); ok {
+			m.Inputs <- in
+		}
+		data = data[:0]
+	}
+}
+
+// midiInput turns a two byte MIDI channel message into input
+func midiInput(status, d1, d2 byte) (Input, bool) {
+	switch status & 0xf0 {
+	case 0xb0: // control change
+		axis, ok := config.MidiCC[int(d1)]
+		if !ok {
+			return Input{}, false
+		}
+		// 0 to 127 with 64 in the middle
+		return Input{Player: config.MidiPlayer, Kind: INPUT_AXIS, Index: axis, Value: int16((int(d2) - 64) * 512)}, true
+	case 0x90: // note on, velocity 0 means off
+		var v int16
+		if d2 > 0 {
+			v = 1
+		}
+		return Input{Player: config.MidiPlayer, Kind: INPUT_BUTTON, Index: int(d1) % 64, Value: v}, true
+	case 0x80: // note off
+		return Input{Player: config.MidiPlayer, Kind: INPUT_BUTTON, Index: int(d1) % 64, Value: 0}, true
+	}
+	return Input{}, false
+}
+
+// Close the device
+func (m *MIDI) Close() {
+	m.f.Close()
+}
+
+// parseMidiCC reads which controllers move which axes, as "cc=axis" separated by commas
+func parseMidiCC(s string) (map[int]int, error) {
+	ccs := make(map[int]int)
+	for _, b := range strings.Split(s, ",") {
+		if b = strings.TrimSpace(b); b == "" {
+			continue
+		}
+		parts := strings.SplitN(b, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("bad midi controller %q, expected cc=axis", b)
+		}
+		cc, err := strconv.Atoi(parts[0])
+		if err != nil || cc < 0 || cc > 127 {
+			return nil, fmt.Errorf("bad controller number in %q", b)
+		}
+		axis, err := strconv.Atoi(parts[1])
+		if err != nil || axis < 0 {
+			return nil, fmt.Errorf("bad axis in %q", b)
+		}
+		ccs[cc] = axis
+	}
+	return ccs, nil
+}
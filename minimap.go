@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+const (
+	// width of the minimap, it has the same shape as the screen and so the playfield
+	MINIMAP_W = 192
+
+	MINIMAP_BACK  = uint32(0x00101010)
+	MINIMAP_FRAME = uint32(0x00606060)
+	MINIMAP_GOAL  = uint32(0x00ffff00) // the goal to collect next
+)
+
+// A Minimap shows the whole playfield small when it is bigger than the screen: where the goals and the
+// players are and which part the screen shows, so the players can find their way to the next goal
+type Minimap struct {
+	Game   *Game
+	Camera Camera
+}
+
+// Get the box the minimap covers, in the bottom right corner above the HUD
+func (mm Minimap) Rect() *sdl.Rect {
+	h := MINIMAP_W * frameH / frameW
+	return &sdl.Rect{int16(frameW - MINIMAP_W - 10), int16(frameH - HUD_HEIGHT - h - 10), MINIMAP_W, uint16(h)}
+}
+
+// Draw the minimap
+func (mm Minimap) Draw(screen *sdl.Surface) {
+	w, h := worldSize()
+	r := mm.Rect()
+	mx0, my0, mw, mh := int(r.X), int(r.Y), int(r.W), int(r.H)
+	// dot draws a dot of the given size at the world position x, y
+	dot := func(x, y, size int, color uint32) {
+		mx, my := mx0+x*mw/w, my0+y*mh/h
+		screen.FillRect(&sdl.Rect{int16(mx - size/2), int16(my - size/2), uint16(size), uint16(size)}, color)
+	}
+	screen.FillRect(r, MINIMAP_BACK)
+	screen.SetClipRect(r)
+	current := mm.Game.Current()
+	for _, goal := range mm.Game.Visible() {
+		if goal != current {
+			dot(goal.X, goal.Y, 3, packColor(config.GoalColor))
+		}
+	}
+	if current != nil {
+		dot(current.X, current.Y, 7, MINIMAP_GOAL)
+	}
+	for _, m := range mm.Game.Markers {
+		dot(m.X, m.Y, 5, m.Color)
+	}
+	// the part of the playfield on the screen, which wraps around like the view does
+	vw, vh := frameW*mw/w, frameH*mh/h
+	x := mx0 + (mm.Camera.X-frameW/2)*mw/w
+	y := my0 + (mm.Camera.Y-frameH/2)*mh/h
+	for _, dx := range []int{-mw, 0, mw} {
+		for _, dy := range []int{-mh, 0, mh} {
+			Outline{R: sdl.Rect{int16(x + dx), int16(y + dy), uint16(vw), uint16(vh)}, Color: MINIMAP_FRAME, Width: 1}.Draw(screen)
+		}
+	}
+	screen.SetClipRect(nil)
+	Outline{R: *r, Color: MINIMAP_FRAME, Width: 1}.Draw(screen)
+}
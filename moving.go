@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+)
+
+// moveGoals drifts the visible goals around the screen at config.GoalSpeed, bouncing off the edges.
+// Each goal sets off in a random direction the first time it moves.  Goals in a maze stay put, they
+// would only get stuck in the walls.
+func (g *Game) moveGoals() {
+	if config.GoalSpeed <= 0 || g.Maze != nil {
+		return
+	}
+	step := config.GoalSpeed / float64(config.UpdateRate)
+	w, h := worldSize()
+	for _, goal := range g.Visible() {
+		if goal.VX == 0 && goal.VY == 0 {
+			angle := 2 * math.Pi * g.rng.Float64()
+			goal.VX, goal.VY = math.Cos(angle), math.Sin(angle)
+		}
+		if int(goal.fx) != goal.X || int(goal.fy) != goal.Y {
+			// the goal was put somewhere new
+			goal.fx, goal.fy = float64(goal.X), float64(goal.Y)
+		}
+		goal.fx, goal.VX = bounce(goal.fx+goal.VX*step, goal.VX, float64(goal.W)/2, float64(w-goal.W/2))
+		goal.fy, goal.VY = bounce(goal.fy+goal.VY*step, goal.VY, float64(goal.H)/2, float64(h-goal.H/2))
+		goal.X, goal.Y = int(goal.fx), int(goal.fy)
+	}
+}
+
+// bounce keeps pos between lo and hi, reflecting it and its direction v off whichever end it went past
+func bounce(pos, v, lo, hi float64) (float64, float64) {
+	switch {
+	case pos < lo:
+		return 2*lo - pos, math.Abs(v)
+	case pos > hi:
+		return 2*hi - pos, -math.Abs(v)
+	}
+	return pos, v
+}
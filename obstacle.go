@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// What touching an obstacle does
+const (
+	OBSTACLE_POINTS = "points" // the player loses OBSTACLE_COST points
+	OBSTACLE_RESET  = "reset"  // the marker goes back to the middle of its area
+)
+
+const (
+	// points lost for touching an obstacle
+	OBSTACLE_COST = 5
+	// width and height of an obstacle
+	OBSTACLE_SIZE = 60
+	// obstacle color
+	OBSTACLE_COLOR = 0x00a02020
+)
+
+// A Collidable is something on the screen that does something to the markers that touch it
+type Collidable interface {
+	Drawable
+	// Touch is called when a marker of player starts touching it
+	Touch(g *Game, player int, m *Marker)
+}
+
+// contact is a marker touching a Collidable, by index into Game.Objects and player
+type contact struct {
+	obj, player int
+	hand        bool
+}
+
+// checkObjects tells the objects about any markers that have just started touching them
+func (g *Game) checkObjects() {
+	if len(g.Objects) == 0 {
+		return
+	}
+	touching := make(map[contact]bool)
+	check := func(c contact, m *Marker) {
+		if !m.Intersects(g.Objects[c.obj].Rect()) {
+			return
+		}
+		touching[c] = true
+		if !g.touching[c] {
+			g.Objects[c.obj].Touch(g, c.player, m)
+		}
+	}
+	for o := range g.Objects {
+		for i := range g.Markers {
+			check(contact{o, i, false}, &g.Markers[i])
+		}
+		for i := range g.Hands {
+			check(contact{o, i, true}, &g.Hands[i])
+		}
+	}
+	g.touching = touching
+}
+
+// An Obstacle is a block the players should keep away from
+type Obstacle struct {
+	R sdl.Rect
+}
+
+// Get the bounding rectangle of the obstacle
+func (o Obstacle) Rect() *sdl.Rect {
+	r := o.R
+	return &r
+}
+
+// Draw the obstacle
+func (o Obstacle) Draw(screen *sdl.Surface) {
+	screen.FillRect(o.Rect(), OBSTACLE_COLOR)
+}
+
+// Touch costs the player points or sends the marker back to the middle, as config.ObstaclePenalty says
+func (o Obstacle) Touch(g *Game, player int, m *Marker) {
+	if config.ObstaclePenalty == OBSTACLE_RESET {
+		x, y, w, h := m.Area()
+		m.X, m.Y = x+w/2, y+h/2
+		m.PrevX, m.PrevY = m.X, m.Y
+	} else {
+		p := &g.Markers[player]
+		if p.Score -= OBSTACLE_COST; p.Score < 0 {
+			p.Score = 0
+		}
+	}
+	g.event(fmt.Sprintf("obstacle %d", player))
+}
+
+// AddObstacles puts n obstacles at random places on the screen, clear of the middle where the
+// markers start
+func (g *Game) AddObstacles(n int) {
+	w, h := worldSize()
+	middle := &sdl.Rect{int16(w/2 - OBSTACLE_SIZE), int16(h/2 - OBSTACLE_SIZE), 2 * OBSTACLE_SIZE, 2 * OBSTACLE_SIZE}
+	for len(g.Objects) < n {
+		r := sdl.Rect{int16(g.rng.Intn(w - OBSTACLE_SIZE)), int16(g.rng.Intn(h - OBSTACLE_SIZE)), OBSTACLE_SIZE, OBSTACLE_SIZE}
+		if !intersects(&r, middle) {
+			g.Objects = append(g.Objects, Obstacle{r})
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// how long a wrong goal flashes after it was touched
+	WRONG_FLASH_TIME = 600 * time.Millisecond
+	// how long each on and off of the flash lasts
+	WRONG_BLINK = 100 * time.Millisecond
+	// the flash color
+	WRONG_COLOR = 0x00ff0000
+)
+
+// Visible returns the goals to draw.  In ordered mode the next few goals are shown along with the
+// current one, in free play all the goals not yet collected, otherwise only the current goal is shown.
+func (g *Game) Visible() []*Goal {
+	if config.Mode == MODE_RACE {
+		return g.RaceGoals()
+	}
+	if config.Mode == MODE_PAINT || config.Mode == MODE_TRACK {
+		return nil
+	}
+	if config.Mode == MODE_SIMON {
+		return g.simonPool()
+	}
+	if config.Mode == MODE_FREE && g.CurGoal < len(g.Goals) {
+		return g.Goals[g.CurGoal:]
+	}
+	cur := g.Current()
+	if cur == nil {
+		return nil
+	}
+	if config.Mode != MODE_ORDERED {
+		return []*Goal{cur}
+	}
+	end := g.CurGoal + 1 + config.Preview
+	if end > len(g.Goals) {
+		end = len(g.Goals)
+	}
+	return g.Goals[g.CurGoal:end]
+}
+
+// checkWrongGoals flashes any goal other than the current one that a marker touches, and pushes the
+// marker away from it
+func (g *Game) checkWrongGoals() {
+	cur := g.Current()
+	for _, goal := range g.Visible() {
+		if goal == cur {
+			continue
+		}
+		r := goal.Rect()
+		for i := range g.Markers {
+			m := &g.Markers[i]
+			if !m.Intersects(r) {
+				continue
+			}
+			if g.wrongGoal != goal || g.Clock >= g.wrongUntil {
+				g.event("wrong " + goal.Text)
+			}
+			g.wrongGoal, g.wrongUntil = goal, g.Clock+WRONG_FLASH_TIME
+			if config.PushStrength > 0 {
+				m.Push(pushVector(m.X, m.Y, goal.X, goal.Y, config.PushStrength))
+			}
+		}
+	}
+}
+
+// WrongFlash gives the red flash around a wrong goal that was touched, or nil if the goal is not
+// flashing right now
+func (g *Game) WrongFlash(goal *Goal) Drawable {
+	if goal != g.wrongGoal || g.Clock >= g.wrongUntil {
+		return nil
+	}
+	if (g.wrongUntil-g.Clock)/WRONG_BLINK%2 == 1 {
+		return nil
+	}
+	return Outline{R: *goal.Rect(), Color: WRONG_COLOR, Width: 4}
+}
+
+// pushVector gives a move of length strength pointing from the goal at gx, gy towards the marker at
+// mx, my.  A marker sitting right on the goal is pushed up.
+func pushVector(mx, my, gx, gy int, strength float64) (dx, dy int) {
+	vx, vy := float64(mx-gx), float64(my-gy)
+	l := math.Hypot(vx, vy)
+	if l == 0 {
+		return 0, -int(strength)
+	}
+	return int(math.Floor(vx/l*strength + 0.5)), int(math.Floor(vy/l*strength + 0.5))
+}
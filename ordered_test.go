@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPushVector(t *testing.T) {
+	tests := []struct {
+		name           string
+		mx, my, gx, gy int
+		strength       float64
+		wantX, wantY   int
+	}{
+		{"right of the goal", 110, 100, 100, 100, 20, 20, 0},
+		{"above the goal", 100, 50, 100, 100, 20, 0, -20},
+		{"diagonal", 110, 110, 100, 100, 10, 7, 7},
+		{"on the goal", 100, 100, 100, 100, 15, 0, -15},
+		{"no strength", 110, 100, 100, 100, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		dx, dy := pushVector(tt.mx, tt.my, tt.gx, tt.gy, tt.strength)
+		if dx != tt.wantX || dy != tt.wantY {
+			t.Errorf("%s: pushed %d,%d, want %d,%d", tt.name, dx, dy, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+// orderedGame gives a game in ordered mode with goals A, B and C in a row and one marker far from them
+func orderedGame(t *testing.T) *Game {
+	useConfig(t, Config{Mode: MODE_ORDERED, Preview: 1, CollectButton: -1, PushStrength: 30, EndPolicy: END_STOP})
+	var goals []*Goal
+	for i, text := range []string{"A", "B", "C"} {
+		goals = append(goals, &Goal{Text: text, Order: i, X: 200 + 200*i, Y: 300, W: 40, H: 40})
+	}
+	g := NewGame([]Marker{{X: 100, Y: 600}}, goals)
+	g.Update()
+	return g
+}
+
+func TestOrderedProgression(t *testing.T) {
+	g := orderedGame(t)
+	m := &g.Markers[0]
+	tests := []struct {
+		name        string
+		goal        int // the goal the marker is put on
+		wantCurrent int
+		wantVisible int
+	}{
+		{"the next goal is shown too", -1, 0, 2},
+		{"the wrong goal is not collected", 1, 0, 2},
+		{"the right one is", 0, 1, 2},
+		{"then the next", 1, 2, 1},
+	}
+	for _, tt := range tests {
+		if tt.goal >= 0 {
+			m.X, m.Y = g.Goals[tt.goal].X, g.Goals[tt.goal].Y
+			g.Update()
+		}
+		if g.CurGoal != tt.wantCurrent || len(g.Visible()) != tt.wantVisible {
+			t.Errorf("%s: current %d with %d visible, want %d with %d", tt.name, g.CurGoal, len(g.Visible()), tt.wantCurrent, tt.wantVisible)
+		}
+	}
+}
+
+func TestWrongGoalPushesAway(t *testing.T) {
+	g := orderedGame(t)
+	m := &g.Markers[0]
+	// just right of the wrong goal B
+	m.X, m.Y = g.Goals[1].X+10, g.Goals[1].Y
+	g.Update()
+	if m.X != g.Goals[1].X+40 || m.Y != g.Goals[1].Y {
+		t.Errorf("marker at %d,%d, want it pushed right to %d,%d", m.X, m.Y, g.Goals[1].X+40, g.Goals[1].Y)
+	}
+	if g.WrongFlash(g.Goals[1]) == nil {
+		t.Errorf("the wrong goal is not flashing")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+)
+
+// the colors the paint mode brushes cycle through
+var paintColors = []uint32{0x00ff4040, 0x00ffa000, 0x00ffff40, 0x0040ff40, 0x004080ff, 0x00c060ff, 0x00ffffff}
+
+// paintButton handles the clear and color buttons in paint mode.  It returns true if the button was
+// one of them.
+func (g *Game) paintButton(player, button int, pressed bool) bool {
+	switch button {
+	case config.ClearButton:
+		if pressed {
+			g.Cleared++
+		}
+	case config.ColorButton:
+		if pressed {
+			m := &g.Markers[player]
+			m.Paint = (m.Paint + 1) % len(paintColors)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// A Canvas keeps what the markers have painted in paint mode
+type Canvas struct {
+	Surface    *sdl.Surface
+	background *Background // what a cleared canvas shows
+	cleared    int         // the game's Cleared when the canvas was last cleared
+}
+
+// Create an empty canvas the size of the playfield, showing the background
+func NewCanvas(background *Background) *Canvas {
+	w, h := worldSize()
+	c := &Canvas{Surface: sdl.CreateRGBSurface(sdl.SWSURFACE, w, h, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0), background: background}
+	c.background.Fill(c.Surface)
+	return c
+}
+
+// Paint adds the markers' moves in the last update to the canvas, clearing it first if a player
+// asked for that
+func (c *Canvas) Paint(g *Game) {
+	if g.Cleared != c.cleared {
+		c.cleared = g.Cleared
+		c.background.Fill(c.Surface)
+	}
+	for i := range g.Markers {
+		c.stroke(&g.Markers[i], paintColors[g.Markers[i].Paint])
+	}
+	for i := range g.Hands {
+		c.stroke(&g.Hands[i], paintColors[g.Markers[i].Paint])
+	}
+}
+
+// stroke paints the marker's path from its previous position to where it is now.  A marker that
+// wrapped around the edge only leaves a dab where it came out.
+func (c *Canvas) stroke(m *Marker, color uint32) {
+	r := m.Rect()
+	dx, dy := float64(m.X-m.PrevX), float64(m.Y-m.PrevY)
+	_, _, w, h := m.Area()
+	steps := int(math.Max(math.Abs(dx), math.Abs(dy)))
+	if math.Abs(dx) > float64(w/2) || math.Abs(dy) > float64(h/2) {
+		steps = 0
+	}
+	for s := 0; s <= steps; s++ {
+		f := 1.0
+		if steps > 0 {
+			f = float64(s) / float64(steps)
+		}
+		x, y := m.PrevX+int(dx*f), m.PrevY+int(dy*f)
+		if steps == 0 {
+			x, y = m.X, m.Y
+		}
+		c.Surface.FillRect(&sdl.Rect{int16(x - int(r.W)/2), int16(y - int(r.H)/2), r.W, r.H}, color)
+	}
+}
+
+// Draw the canvas
+func (c *Canvas) Draw(screen *sdl.Surface) {
+	screen.Blit(&sdl.Rect{0, 0, 0, 0}, c.Surface, nil)
+}
+
+// Get the area of the canvas
+func (c *Canvas) Rect() *sdl.Rect {
+	return &sdl.Rect{0, 0, uint16(c.Surface.W), uint16(c.Surface.H)}
+}
+
+// Free the canvas
+func (c *Canvas) Free() {
+	c.Surface.Free()
+}
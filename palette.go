@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// Marker color palettes
+const (
+	PALETTE_CLASSIC   = "classic"   // the original red, green and blue
+	PALETTE_OKABE_ITO = "okabe-ito" // Okabe and Ito's colors, safe for all common kinds of colorblindness
+	PALETTE_TOL       = "tol"       // Paul Tol's bright scheme, also colorblind safe
+	PALETTE_CONTRAST  = "contrast"  // few colors, far apart in lightness, for very poor color vision
+)
+
+// the colors of the first players with each palette, later players get colors made up by playerColor
+var palettes = map[string][]uint32{
+	PALETTE_CLASSIC:   markerColors[:],
+	PALETTE_OKABE_ITO: {0x00e69f00, 0x0056b4e9, 0x00009e73, 0x00f0e442, 0x000072b2, 0x00d55e00, 0x00cc79a7},
+	PALETTE_TOL:       {0x004477aa, 0x00ee6677, 0x00228833, 0x00ccbb44, 0x0066ccee, 0x00aa3377},
+	PALETTE_CONTRAST:  {0x00ffffff, 0x00ddaa33, 0x00004488, 0x00bb5566},
+}
+
+// Marker patterns, drawn over the marker's color with -patterns so players can be told apart by
+// more than color.  Player i gets pattern i, wrapping around.
+const (
+	PATTERN_SOLID = iota
+	PATTERN_HSTRIPES
+	PATTERN_VSTRIPES
+	PATTERN_CHECKS
+	PATTERN_CROSS
+	PATTERN_HOLE
+	PATTERN_COUNT
+)
+
+// how wide the stripes and checks of the patterns are
+const PATTERN_STEP = 4
+
+// the color the patterns are drawn in
+const PATTERN_COLOR = uint32(0x00000000)
+
+// drawPattern draws the pattern over r
+func drawPattern(screen *sdl.Surface, r *sdl.Rect, pattern int) {
+	x, y, w, h := int(r.X), int(r.Y), int(r.W), int(r.H)
+	fill := func(fx, fy, fw, fh int) {
+		screen.FillRect(&sdl.Rect{int16(fx), int16(fy), uint16(fw), uint16(fh)}, PATTERN_COLOR)
+	}
+	switch pattern % PATTERN_COUNT {
+	case PATTERN_HSTRIPES:
+		for sy := PATTERN_STEP; sy+PATTERN_STEP <= h; sy += 2 * PATTERN_STEP {
+			fill(x, y+sy, w, PATTERN_STEP)
+		}
+	case PATTERN_VSTRIPES:
+		for sx := PATTERN_STEP; sx+PATTERN_STEP <= w; sx += 2 * PATTERN_STEP {
+			fill(x+sx, y, PATTERN_STEP, h)
+		}
+	case PATTERN_CHECKS:
+		for sy := 0; sy+PATTERN_STEP <= h; sy += PATTERN_STEP {
+			for sx := (sy / PATTERN_STEP % 2) * PATTERN_STEP; sx+PATTERN_STEP <= w; sx += 2 * PATTERN_STEP {
+				fill(x+sx, y+sy, PATTERN_STEP, PATTERN_STEP)
+			}
+		}
+	case PATTERN_CROSS:
+		fill(x+w/2-PATTERN_STEP/2, y, PATTERN_STEP, h)
+		fill(x, y+h/2-PATTERN_STEP/2, w, PATTERN_STEP)
+	case PATTERN_HOLE:
+		fill(x+w/4, y+h/4, w/2, h/2)
+	}
+}
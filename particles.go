@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// how many particles a collected goal bursts into
+	PARTICLE_COUNT = 32
+	// how long a particle lives
+	PARTICLE_LIFE = 800 * time.Millisecond
+	// fastest a particle flies out, in pixels per update
+	PARTICLE_SPEED = 8.0
+	// how much particles fall, added to their speed every update
+	PARTICLE_GRAVITY = 0.25
+	// size of a particle when it is new, they shrink as they age
+	PARTICLE_SIZE = 8
+)
+
+// A Particle is one speck of a burst
+type Particle struct {
+	X, Y   float64
+	VX, VY float64
+	Color  uint32
+	Born   time.Duration
+}
+
+// Particles are the bursts shown when goals are collected.  They have their own randomness so they do
+// not change where the game puts the goals.
+type Particles struct {
+	List  []Particle
+	clock time.Duration // the game clock at the last update
+	rng   *rand.Rand
+}
+
+// NewParticles creates an empty particle system
+func NewParticles() *Particles {
+	return &Particles{rng: rand.New(rand.NewSource(config.Seed))}
+}
+
+// Burst adds a burst of particles flying out of the middle of the goal in bright colors
+func (p *Particles) Burst(goal *Goal) {
+	for i := 0; i < PARTICLE_COUNT; i++ {
+		angle := p.rng.Float64() * 2 * math.Pi
+		speed := PARTICLE_SPEED * (0.3 + 0.7*p.rng.Float64())
+		p.List = append(p.List, Particle{
+			X: float64(goal.X), Y: float64(goal.Y),
+			VX: speed * math.Cos(angle), VY: speed * math.Sin(angle),
+			Color: hsv(p.rng.Float64()*360, 0.7, 1),
+			Born:  p.clock,
+		})
+	}
+}
+
+// Update moves the particles on by one step, dropping the ones that have died
+func (p *Particles) Update(clock time.Duration) {
+	p.clock = clock
+	alive := p.List[:0]
+	for _, pt := range p.List {
+		if clock-pt.Born >= PARTICLE_LIFE {
+			continue
+		}
+		pt.X += pt.VX
+		pt.Y += pt.VY
+		pt.VY += PARTICLE_GRAVITY
+		alive = append(alive, pt)
+	}
+	p.List = alive
+}
+
+// Active reports whether there are particles still flying
+func (p *Particles) Active() bool {
+	return p != nil && len(p.List) > 0
+}
+
+// Get the bounding rectangle of the particles, the whole playfield
+func (p *Particles) Rect() *sdl.Rect {
+	w, h := worldSize()
+	return &sdl.Rect{0, 0, uint16(w), uint16(h)}
+}
+
+// Draw the particles, shrinking as they get older
+func (p *Particles) Draw(screen *sdl.Surface) {
+	for _, pt := range p.List {
+		size := int(PARTICLE_SIZE * (1 - float64(p.clock-pt.Born)/float64(PARTICLE_LIFE)))
+		if size < 1 {
+			size = 1
+		}
+		screen.FillRect(&sdl.Rect{int16(int(pt.X) - size/2), int16(int(pt.Y) - size/2), uint16(size), uint16(size)}, pt.Color)
+	}
+}
+
+// burst shows the goal bursting into particles, if they are turned on
+func (g *Game) burst(goal *Goal) {
+	if g.Particles != nil {
+		g.Particles.Burst(goal)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+)
+
+// Pause menu choices
+const (
+	PAUSE_RESUME  = "Resume"
+	PAUSE_RESTART = "Restart"
+	PAUSE_QUIT    = "Quit"
+)
+
+var pauseChoices = []string{PAUSE_RESUME, PAUSE_RESTART, PAUSE_QUIT}
+
+// how far a stick has to be pushed to move through the pause menu
+const PAUSE_AXIS_THRESHOLD = 16000
+
+// A PauseMenu is drawn over the dimmed game while it is paused.  The sticks, hats or arrow keys move
+// through the choices and a button picks one.
+type PauseMenu struct {
+	Selected int
+	title    *Label
+	items    []*Label
+	pushed   map[int]bool // players holding their stick up or down, so holding it only moves once
+}
+
+// Create the pause menu
+func NewPauseMenu(font, bigFont *ttf.Font) *PauseMenu {
+	p := &PauseMenu{title: &Label{Font: bigFont, Color: config.TextColor}, pushed: make(map[int]bool)}
+	p.title.SetText("Paused")
+	p.title.X = (frameW - int(p.title.Rect().W)) / 2
+	p.title.Y = frameH/3 - int(p.title.Rect().H)
+	y := frameH / 2
+	for _, choice := range pauseChoices {
+		l := &Label{Font: font, Color: config.TextColor}
+		l.SetText(choice)
+		l.X, l.Y = (frameW-int(l.Rect().W))/2, y
+		y += int(l.Rect().H) + 20
+		p.items = append(p.items, l)
+	}
+	return p
+}
+
+// Move the selection by dir, going round from the bottom to the top
+func (p *PauseMenu) Move(dir int) {
+	p.Selected = (p.Selected + dir + len(p.items)) % len(p.items)
+}
+
+// Axis moves the selection when a player pushes their stick up or down.  It returns true if the
+// selection moved.
+func (p *PauseMenu) Axis(player, axis int, value int16) bool {
+	if axis != AXIS_LEFTY {
+		return false
+	}
+	if value > -PAUSE_AXIS_THRESHOLD && value < PAUSE_AXIS_THRESHOLD {
+		p.pushed[player] = false
+		return false
+	}
+	if p.pushed[player] {
+		return false
+	}
+	p.pushed[player] = true
+	if value < 0 {
+		p.Move(-1)
+	} else {
+		p.Move(1)
+	}
+	return true
+}
+
+// Hat moves the selection when a hat is pushed up or down
+func (p *PauseMenu) Hat(value uint8) bool {
+	if _, y := hatDirection(value); y != 0 {
+		p.Move(int(y))
+		return true
+	}
+	return false
+}
+
+// Choose picks the selected choice.  It returns the input that does it, and false if the game should
+// quit.
+func (p *PauseMenu) Choose() (Input, bool) {
+	choice := p.Selected
+	p.Selected = 0
+	return gameInput(GAME_MENU, choice), pauseChoices[choice] != PAUSE_QUIT
+}
+
+// Resume leaves the pause menu with the given choice
+func (g *Game) Resume(choice string) {
+	if choice == PAUSE_RESTART {
+		g.Restart()
+	}
+	g.Paused = false
+}
+
+// Draw the menu over the dimmed screen
+func (p *PauseMenu) Draw(screen *sdl.Surface) {
+	dim(screen)
+	p.title.Draw(screen)
+	for i, l := range p.items {
+		l.Draw(screen)
+		if i == p.Selected {
+			r := l.Rect()
+			Outline{R: sdl.Rect{r.X - 10, r.Y - 5, r.W + 20, r.H + 10}, Color: 0x00ffff00, Width: 3}.Draw(screen)
+		}
+	}
+}
+
+// Get the area the menu covers, all of the screen
+func (p *PauseMenu) Rect() *sdl.Rect {
+	return frameRect()
+}
+
+// Free the menu text
+func (p *PauseMenu) Free() {
+	p.title.Free()
+	for _, l := range p.items {
+		l.Free()
+	}
+}
+
+// dim darkens the 32 bit surface to half its brightness
+func dim(s *sdl.Surface) {
+	s.Lock()
+	for y := 0; y < int(s.H); y++ {
+		for x := 0; x < int(s.W); x++ {
+			p := pixelPtr(s, x, y)
+			*p = (*p >> 1) & 0x007f7f7f
+		}
+	}
+	s.Unlock()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// Phone lets a phone or tablet browser join as a player.  It serves a page with a direction pad and a
+// button, and the page sends what is pressed back as small HTTP requests.
+type Phone struct {
+	Inputs chan Input
+
+	listener net.Listener
+}
+
+// ListenPhone starts serving the phone page on addr (e.g. ":8080")
+func ListenPhone(addr string) (*Phone, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	p := &Phone{Inputs: make(chan Input, 16), listener: l}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", p.page)
+	mux.HandleFunc("/input", p.input)
+	go http.Serve(l, mux)
+	fmt.Println("phones can play at http://" + l.Addr().String() + "/")
+	return p, nil
+}
+
+// page serves the direction pad
+func (p *Phone) page(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, phonePage)
+}
+
+// input takes the state of the pad: x and y from -1 to 1, b 1 while the button is held, and optionally
+// the player
+func (p *Phone) input(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	player := config.PhonePlayer
+	if s := q.Get("player"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			http.Error(w, "bad player", http.StatusBadRequest)
+			return
+		}
+		player = n
+	}
+	x, errX := strconv.Atoi(q.Get("x"))
+	y, errY := strconv.Atoi(q.Get("y"))
+	b, errB := strconv.Atoi(q.Get("b"))
+	if errX != nil || errY != nil || errB != nil || x < -1 || x > 1 || y < -1 || y > 1 {
+		http.Error(w, "expected x, y and b", http.StatusBadRequest)
+		return
+	}
+	p.Inputs <- Input{Player: player, Kind: INPUT_AXIS, Index: AXIS_LEFTX, Value: int16(x * 32767)}
+	p.Inputs <- Input{Player: player, Kind: INPUT_AXIS, Index: AXIS_LEFTY, Value: int16(y * 32767)}
+	if b != 0 {
+		b = 1
+	}
+	p.Inputs <- Input{Player: player, Kind: INPUT_BUTTON, Index: BUTTON_A, Value: int16(b)}
+	fmt.Fprintln(w, "ok")
+}
+
+// Close stops serving
+func (p *Phone) Close() {
+	p.listener.Close()
+}
+
+// the page the phones get, a direction pad and a button that report while they are touched
+const phonePage = `<!DOCTYPE html>
+<html><head><meta name="viewport" content="width=device-width, user-scalable=no">
+<style>
+body { margin: 0; background: #202020; display: flex; height: 100vh; align-items: center; justify-content: space-around; touch-action: none; user-select: none; }
+#pad { display: grid; grid-template-columns: repeat(3, 80px); grid-template-rows: repeat(3, 80px); gap: 6px; }
+.key { background: #555; border-radius: 12px; }
+#b { width: 120px; height: 120px; border-radius: 60px; background: #a00; }
+.on { background: #ccc !important; }
+</style></head><body>
+<div id="pad">
+<div></div><div class="key" data-x="0" data-y="-1"></div><div></div>
+<div class="key" data-x="-1" data-y="0"></div><div></div><div class="key" data-x="1" data-y="0"></div>
+<div></div><div class="key" data-x="0" data-y="1"></div><div></div>
+</div>
+<div id="b"></div>
+<script>
+var x = 0, y = 0, b = 0;
+var player = new URLSearchParams(location.search).get("player");
+function send() {
+	var url = "/input?x=" + x + "&y=" + y + "&b=" + b;
+	if (player !== null) url += "&player=" + player;
+	fetch(url);
+}
+document.querySelectorAll(".key").forEach(function (k) {
+	k.addEventListener("pointerdown", function (e) { x = +k.dataset.x; y = +k.dataset.y; k.classList.add("on"); send(); e.preventDefault(); });
+	k.addEventListener("pointerup", function () { x = 0; y = 0; k.classList.remove("on"); send(); });
+	k.addEventListener("pointerleave", function () { if (k.classList.contains("on")) { x = 0; y = 0; k.classList.remove("on"); send(); } });
+});
+var btn = document.getElementById("b");
+btn.addEventListener("pointerdown", function (e) { b = 1; btn.classList.add("on"); send(); e.preventDefault(); });
+btn.addEventListener("pointerup", function () { b = 0; btn.classList.remove("on"); send(); });
+</script>
+</body></html>
+`
@@ -0,0 +1,20 @@
+package main
+
+// velocities smaller than this count as stopped
+const STOP_SPEED = 0.001
+
+// accelerate is the physics option's update of the marker's velocity.  The stick and hat push the
+// velocity by config.Acceleration of their position each update and friction takes config.Friction
+// of it away, so holding the stick still the marker settles at acceleration/friction times the speed
+// it would have without the physics.
+func (m *Marker) accelerate() {
+	a, keep := float32(config.Acceleration), float32(1-config.Friction)
+	m.velX = (m.velX + a*(m.Vax+m.Vhx*HATMULTIPLIER)) * keep
+	m.velY = (m.velY + a*(m.Vay+m.Vhy*HATMULTIPLIER)) * keep
+	if m.velX > -STOP_SPEED && m.velX < STOP_SPEED {
+		m.velX = 0
+	}
+	if m.velY > -STOP_SPEED && m.velY < STOP_SPEED {
+		m.velY = 0
+	}
+}
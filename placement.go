@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// how many random candidates are tried before settling for one that is too close
+const PLACE_TRIES = 50
+
+// A placeHistory remembers where goals were recently placed so new positions can keep away from them.
+type placeHistory struct {
+	points [][2]int
+}
+
+// remember the position x, y, forgetting the oldest ones beyond config.AvoidHistory
+func (h *placeHistory) add(x, y int) {
+	if config.AvoidHistory <= 0 {
+		return
+	}
+	h.points = append(h.points, [2]int{x, y})
+	if over := len(h.points) - config.AvoidHistory; over > 0 {
+		h.points = h.points[over:]
+	}
+}
+
+// farEnough reports whether x, y is at least minDist away from all the remembered positions
+func (h *placeHistory) farEnough(x, y int, minDist float64) bool {
+	if h == nil {
+		return true
+	}
+	for _, p := range h.points {
+		if math.Hypot(float64(x-p[0]), float64(y-p[1])) < minDist {
+			return false
+		}
+	}
+	return true
+}
+
+// place moves the goal to a position given by pick, rejecting candidates that are closer than
+// config.AvoidDistance to the recent positions in h.  If no candidate is far enough the last one
+// is used.  The chosen position is added to h.
+func place(goal *Goal, h *placeHistory, pick func() (int, int)) {
+	x, y := pick()
+	for i := 1; i < PLACE_TRIES && !h.farEnough(x, y, config.AvoidDistance); i++ {
+		x, y = pick()
+	}
+	goal.X, goal.Y = x, y
+	if h != nil {
+		h.add(x, y)
+	}
+}
+
+// placeRandom moves the goal to a random position where it is entirely on the playfield
+func placeRandom(goal *Goal, h *placeHistory, rng *rand.Rand) {
+	place(goal, h, func() (int, int) {
+		width, height := worldSize()
+		return goal.W/2 + rng.Intn(width-goal.W), goal.H/2 + rng.Intn(height-goal.H)
+	})
+}
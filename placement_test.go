@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPlaceKeepsAwayFromRecent(t *testing.T) {
+	tests := []struct {
+		name    string
+		history int
+		avoid   float64
+	}{
+		{"last position", 1, 300},
+		{"last few positions", 3, 250},
+		{"far apart", 2, 400},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{AvoidHistory: tt.history, AvoidDistance: tt.avoid})
+		rng := rand.New(rand.NewSource(1))
+		var h placeHistory
+		goal := &Goal{W: 40, H: 40}
+		var placed [][2]int
+		for i := 0; i < 50; i++ {
+			placeRandom(goal, &h, rng)
+			if goal.X < goal.W/2 || goal.X > WIDTH-goal.W/2 || goal.Y < goal.H/2 || goal.Y > HEIGHT-goal.H/2 {
+				t.Fatalf("%s: goal placed off screen at %d,%d", tt.name, goal.X, goal.Y)
+			}
+			for j := len(placed) - tt.history; j < len(placed); j++ {
+				if j < 0 {
+					continue
+				}
+				if d := math.Hypot(float64(goal.X-placed[j][0]), float64(goal.Y-placed[j][1])); d < tt.avoid {
+					t.Errorf("%s: goal placed %.0f from a recent position, want at least %.0f", tt.name, d, tt.avoid)
+				}
+			}
+			placed = append(placed, [2]int{goal.X, goal.Y})
+		}
+		if len(h.points) != tt.history {
+			t.Errorf("%s: %d positions remembered, want %d", tt.name, len(h.points), tt.history)
+		}
+	}
+}
+
+func TestFarEnough(t *testing.T) {
+	useConfig(t, Config{AvoidHistory: 2})
+	var h placeHistory
+	h.add(100, 100)
+	h.add(500, 500)
+	h.add(900, 100) // pushes out 100,100
+	tests := []struct {
+		x, y int
+		want bool
+	}{
+		{100, 100, true},
+		{510, 500, false},
+		{900, 300, true},
+		{900, 199, false},
+	}
+	for _, tt := range tests {
+		if got := h.farEnough(tt.x, tt.y, 100); got != tt.want {
+			t.Errorf("farEnough(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+	var none *placeHistory
+	if !none.farEnough(0, 0, 100) {
+		t.Errorf("a nil history is not far enough")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// A Profile is how one joystick is set up, so it behaves the same every time it is plugged in.  Unset
+// fields fall back to the command line settings.
+type Profile struct {
+	Deadzone *int           `json:"deadzone,omitempty"`
+	Invert   []int          `json:"invert,omitempty"` // raw axes that point the wrong way
+	Curve    string         `json:"curve,omitempty"`
+	Exponent float64        `json:"exponent,omitempty"`
+	Buttons  map[int]string `json:"buttons,omitempty"` // what each button does, as on the remap screen
+	Color    string         `json:"color,omitempty"`   // marker color as RRGGBB
+	Sprite   string         `json:"sprite,omitempty"`  // asset name of an image to draw for the marker
+}
+
+// loadProfiles reads joystick profiles from a JSON file, an object mapping joystick names to profiles.
+// SDL 1.2 has no device GUIDs, so the name is used.
+func loadProfiles(path string) (map[string]*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[string]*Profile)
+	if err = json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for name, p := range profiles {
+		if p.Color != "" {
+			if _, err = parseColor(p.Color); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", path, name, err)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// inverted reports whether the profile turns an axis around
+func (p *Profile) inverted(axis int) bool {
+	for _, a := range p.Invert {
+		if a == axis {
+			return true
+		}
+	}
+	return false
+}
+
+// color gives the marker color of the profile
+func (p *Profile) color() (uint32, bool) {
+	c, err := parseColor(p.Color)
+	if p.Color == "" || err != nil {
+		return 0, false
+	}
+	return packColor(c), true
+}
+
+// Profile gives the profile of the stick with SDL index which, nil if it has none
+func (s *Sticks) Profile(which int) *Profile {
+	if which < 0 || which >= len(s.Names) {
+		return nil
+	}
+	return s.Profiles[s.Names[which]]
+}
+
+// Axis takes a raw axis value of the stick with SDL index which through drift, calibration, deadzone,
+// inversion, mapping to a logical axis and the response curve.  It returns false for axes that are
+// not used.
+func (s *Sticks) Axis(which, axis int, v int16) (int, int16, bool) {
+	p := s.Profile(which)
+	if s.Drift != nil && which < len(s.Names) {
+		v = s.Drift.Apply(s.Names[which], axis, v)
+	}
+	v = s.Normalize(which, axis, v)
+	if p != nil && p.Deadzone != nil {
+		v = Deadzones{Default: *p.Deadzone}.Apply(which, axis, v)
+	} else {
+		v = config.Deadzones.Apply(which, axis, v)
+	}
+	v = config.Invert.Apply(which, axis, v)
+	if p != nil && p.inverted(axis) {
+		v = invertAxis(v)
+	}
+	logical, v, ok := s.MapAxis(which, axis, v)
+	if !ok {
+		return 0, 0, false
+	}
+	if logical == AXIS_LEFTY && config.FlipY {
+		v = invertAxis(v)
+	}
+	if logical == AXIS_LEFTX || logical == AXIS_LEFTY || logical == AXIS_RIGHTX || logical == AXIS_RIGHTY {
+		curve, exponent := config.Curve, config.CurveExponent
+		if p != nil && p.Curve != "" {
+			curve = p.Curve
+			if p.Exponent != 0 {
+				exponent = p.Exponent
+			}
+		}
+		v = applyCurve(v, curve, exponent)
+	}
+	return logical, v, true
+}
+
+// applyProfiles gives the players the marker colors and sprites from their sticks' profiles
+func (s *Sticks) applyProfiles(g *Game) {
+	dev := 0
+	for i := range s.Names {
+		if !s.playing(i) {
+			continue
+		}
+		if p := s.Profile(i); p != nil {
+			if player, ok := config.Inputs.MovePlayer(dev); ok && player < len(g.Markers) {
+				if c, ok := p.color(); ok {
+					g.Markers[player].Color = c
+				}
+				if p.Sprite != "" && g.Sprite != nil {
+					g.Markers[player].Sprite = g.Sprite(p.Sprite)
+				}
+			}
+		}
+		dev++
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+	"time"
+)
+
+const (
+	// how long one pulse of the current goal takes
+	PULSE_PERIOD = 1500 * time.Millisecond
+	// how much bigger the goal gets at the top of a pulse
+	PULSE_AMOUNT = 0.15
+	// how many sizes a pulse goes through, each is scaled once and kept
+	PULSE_STEPS = 8
+)
+
+// A PulsingGoal is a Drawable showing the current goal part way through a pulse
+type PulsingGoal struct {
+	Goal *Goal
+	Step int // 0 (normal size) to PULSE_STEPS-1 (biggest)
+}
+
+// Pulse wraps the goal to make it pulse if it is the current one, fully revealed and pulsing is on.
+// Otherwise it gives what Reveal gives.
+func (g *Game) Pulse(goal *Goal) Drawable {
+	d := g.Reveal(goal)
+	if !g.Pulsing() || goal != g.Current() || d != Drawable(goal) || config.HighContrast {
+		return d
+	}
+	// the size follows a sine wave, rounded to one of the steps
+	phase := float64(g.Clock%PULSE_PERIOD) / float64(PULSE_PERIOD)
+	size := (1 - math.Cos(2*math.Pi*phase)) / 2
+	return PulsingGoal{goal, int(size*(PULSE_STEPS-1) + 0.5)}
+}
+
+// Pulsing reports whether the current goal is pulsing, which needs it drawn every frame
+func (g *Game) Pulsing() bool {
+	return config.Pulse && g.AssistsEnabled() && !g.Won && config.Mode != MODE_FREE && g.Current() != nil
+}
+
+// pulseScale gives how much a goal is scaled at a step of its pulse
+func pulseScale(step int) float64 {
+	return 1 + PULSE_AMOUNT*float64(step)/float64(PULSE_STEPS-1)
+}
+
+// pulseFrame gives the goal's surface scaled for a step of its pulse, scaling it the first time.  The
+// frames are made again if Surface changes.
+func (g *Goal) pulseFrame(step int) *sdl.Surface {
+	if step == 0 {
+		return g.Surface
+	}
+	if g.pulseOf != g.Surface {
+		g.freePulse()
+		g.pulseOf = g.Surface
+	}
+	if g.pulse == nil {
+		g.pulse = make([]*sdl.Surface, PULSE_STEPS)
+	}
+	if g.pulse[step] == nil {
+		s := pulseScale(step)
+		g.pulse[step] = scaleSurface(g.Surface, int(float64(g.Surface.W)*s), int(float64(g.Surface.H)*s))
+	}
+	return g.pulse[step]
+}
+
+// freePulse releases the scaled frames of the pulse
+func (g *Goal) freePulse() {
+	for _, s := range g.pulse {
+		if s != nil {
+			s.Free()
+		}
+	}
+	g.pulse, g.pulseOf = nil, nil
+}
+
+// Get the bounding rectangle of the goal, which does not pulse
+func (p PulsingGoal) Rect() *sdl.Rect {
+	return p.Goal.Rect()
+}
+
+// DrawRect gives the part of the screen the goal covers at its biggest
+func (p PulsingGoal) DrawRect() *sdl.Rect {
+	g := p.Goal
+	w, h := int(float64(g.W)*pulseScale(PULSE_STEPS-1)), int(float64(g.H)*pulseScale(PULSE_STEPS-1))
+	return &sdl.Rect{int16(g.X - w/2), int16(g.Y - h/2), uint16(w), uint16(h)}
+}
+
+// Draw the goal at its size in the pulse, centred where it always is
+func (p PulsingGoal) Draw(screen *sdl.Surface) {
+	g := p.Goal
+	if g.Hidden || g.Surface == nil {
+		return
+	}
+	s := g.pulseFrame(p.Step)
+	if s == nil {
+		g.Draw(screen)
+		return
+	}
+	screen.Blit(&sdl.Rect{int16(g.X - int(s.W)/2), int16(g.Y - int(s.H)/2), 0, 0}, s, nil)
+}
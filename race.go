@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// A Lane is one player's own run through the goals in race mode
+type Lane struct {
+	Goals []*Goal
+	At    int           // the goal the player is on
+	shown time.Duration // when that goal appeared
+}
+
+// StartRace gives every player their own set of goals from build, placed differently for each
+func (g *Game) StartRace(build func(rng *rand.Rand) []*Goal) {
+	for _, goal := range g.Goals {
+		goal.Free()
+	}
+	g.Goals = nil
+	g.Lanes = make([]Lane, len(g.Markers))
+	for i := range g.Lanes {
+		g.Lanes[i].Goals = build(g.rng)
+		g.goalsMade(g.Lanes[i].Goals, len(g.Lanes[i].Goals))
+	}
+	g.Winner = -1
+}
+
+// updateRace collects the goals the players reach in their own lanes.  The first to get through all
+// of theirs wins.
+func (g *Game) updateRace() {
+	for i := range g.Lanes {
+		lane := &g.Lanes[i]
+		goal := lane.Goals[lane.At]
+		r := goal.Rect()
+		m := &g.Markers[i]
+		on := m.Intersects(r) && m.Settled(goal) || i < len(g.Hands) && g.Hands[i].Intersects(r) && g.Hands[i].Settled(goal)
+		if !m.CanCollect() || !on {
+			continue
+		}
+		m.Score += goalPoints(g.Clock - lane.shown)
+		g.rumble(i)
+		g.Collected++
+		if g.OnCollect != nil {
+			g.OnCollect(goal, len(lane.Goals))
+		}
+		g.event("collect " + goal.Text)
+		g.burst(goal)
+		lane.At++
+		lane.shown = g.Clock
+		if lane.At == len(lane.Goals) {
+			g.Won = true
+			g.Winner = i
+			g.Celebrate(true)
+			g.event("won")
+			return
+		}
+	}
+}
+
+// RaceGoals gives the goal each player is racing for
+func (g *Game) RaceGoals() []*Goal {
+	var goals []*Goal
+	for i := range g.Lanes {
+		if lane := &g.Lanes[i]; lane.At < len(lane.Goals) {
+			goals = append(goals, lane.Goals[lane.At])
+		}
+	}
+	return goals
+}
+
+// RaceOutlines gives a border in each player's color around the goal they are racing for
+func (g *Game) RaceOutlines() []Drawable {
+	var outlines []Drawable
+	for i := range g.Lanes {
+		if lane := &g.Lanes[i]; lane.At < len(lane.Goals) {
+			outlines = append(outlines, Outline{R: *lane.Goals[lane.At].Rect(), Color: g.Markers[i].Color, Width: 3})
+		}
+	}
+	return outlines
+}
+
+// Leader gives the player furthest through their goals, or -1 if nobody is ahead
+func (g *Game) Leader() int {
+	best, leader := -1, -1
+	for i := range g.Lanes {
+		switch at := g.Lanes[i].At; {
+		case at > best:
+			best, leader = at, i
+		case at == best:
+			leader = -1
+		}
+	}
+	return leader
+}
+
+// raceResults gives the winner of the race and how far everybody got, for the results screen
+func raceResults(g *Game) (title string, lines []string) {
+	for i := range g.Lanes {
+		lines = append(lines, fmt.Sprintf("P%d: %d/%d goals, %d points", i+1, g.Lanes[i].At, len(g.Lanes[i].Goals), g.Markers[i].Score))
+	}
+	return fmt.Sprintf("Player %d wins!", g.Winner+1), lines
+}
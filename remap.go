@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/ttf"
+	"os"
+)
+
+// What a button does
+const (
+	ACTION_GROW    = "grow"    // make the marker big while held
+	ACTION_COLLECT = "collect" // collect the goal the marker is on, players with a collect button must press it
+	ACTION_PAUSE   = "pause"   // pause or resume the game
+	ACTION_NONE    = "none"
+)
+
+// the actions the remap screen asks for, in order
+var remapActions = []string{ACTION_GROW, ACTION_COLLECT, ACTION_PAUSE}
+
+// ButtonActions maps joystick names to what each of their buttons does.  Sticks that were never
+// remapped grow the marker with every button.
+type ButtonActions map[string]map[int]string
+
+// loadButtonActions reads the button actions saved by save.  A missing file is the same as an empty one.
+func loadButtonActions(path string) (ButtonActions, error) {
+	actions := make(ButtonActions)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return actions, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &actions); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return actions, nil
+}
+
+// save the button actions as JSON
+func (ba ButtonActions) save(path string) error {
+	data, err := json.MarshalIndent(ba, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Action gives what button does on the named joystick.  Buttons a remapped stick was not given an
+// action for do nothing.
+func (ba ButtonActions) Action(name string, button int) string {
+	buttons, ok := ba[name]
+	if !ok {
+		return ACTION_GROW
+	}
+	if action, ok := buttons[button]; ok {
+		return action
+	}
+	return ACTION_NONE
+}
+
+// A Remapper runs the button remapping screen.  The first button pressed picks the stick being remapped
+// and each press after that is given the next action.
+type Remapper struct {
+	Prompt  *Label
+	dev     int
+	step    int
+	buttons map[int]string
+}
+
+// Start remapping the buttons of a stick
+func NewRemapper(font *ttf.Font) *Remapper {
+	r := &Remapper{Prompt: &Label{Font: font, Color: config.TextColor}, dev: -1, buttons: make(map[int]string)}
+	r.prompt()
+	return r
+}
+
+func (r *Remapper) prompt() {
+	r.Prompt.SetText(fmt.Sprintf("Press the button to %s", remapActions[r.step]))
+	r.Prompt.X = (frameW - int(r.Prompt.Rect().W)) / 2
+	r.Prompt.Y = (frameH - int(r.Prompt.Rect().H)) / 2
+}
+
+// Button gives the next action to a button of device dev.  It returns true when every action has a
+// button.
+func (r *Remapper) Button(dev, button int) bool {
+	if r.dev < 0 {
+		r.dev = dev
+	}
+	if dev != r.dev {
+		return false
+	}
+	if _, taken := r.buttons[button]; taken {
+		return false
+	}
+	r.buttons[button] = remapActions[r.step]
+	r.step++
+	if r.step == len(remapActions) {
+		return true
+	}
+	r.prompt()
+	return false
+}
+
+// Save the new mapping into actions, names gives the name of each device
+func (r *Remapper) Save(actions ButtonActions, names []string) {
+	if r.dev >= 0 && r.dev < len(names) {
+		actions[names[r.dev]] = r.buttons
+	}
+}
+
+// Free the prompt
+func (r *Remapper) Free() {
+	r.Prompt.Free()
+}
+
+// Collect handles the collect button being pressed or released
+func (m *Marker) Collect(pressed bool) {
+	m.hasCollect = true
+	if pressed && !m.Collecting {
+		m.collectPressed = true
+	}
+	m.Collecting = pressed
+}
+
+// CanCollect reports whether the marker collects goals it touches.  With config.CollectButton that
+// is only when a collect button was just pressed, otherwise players with a collect button have to
+// hold it.
+func (m *Marker) CanCollect() bool {
+	if config.CollectButton >= 0 {
+		return m.collectPressed
+	}
+	return !m.hasCollect || m.Collecting
+}
+
+// forgetPresses drops the collect presses made before this update, a press only counts when it is
+// made on the goal
+func (g *Game) forgetPresses() {
+	for i := range g.Markers {
+		g.Markers[i].collectPressed = false
+	}
+}
+
+// TogglePause pauses or resumes the game
+func (g *Game) TogglePause() {
+	g.Paused = !g.Paused
+}
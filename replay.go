@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// A Recorder writes the input for every simulation step to a file, so the session can be replayed.
+// The file starts with the seed and update rate, which the replay needs to come out the same, then
+// has a line for each input: the step number, player, kind, index and value.  The last line gives
+// how many steps were taken.
+type Recorder struct {
+	f     *os.File
+	w     *bufio.Writer
+	steps int // how many steps have been recorded
+}
+
+// Start recording to path
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{f: f, w: bufio.NewWriter(f)}
+	fmt.Fprintf(r.w, "seed %d rate %d\n", config.Seed, config.UpdateRate)
+	return r, nil
+}
+
+// Record the inputs for a step
+func (r *Recorder) Record(step int, inputs []Input) {
+	for _, in := range inputs {
+		fmt.Fprintf(r.w, "%d %d %d %d %d\n", step, in.Player, in.Kind, in.Index, in.Value)
+	}
+	r.steps = step + 1
+}
+
+// Close finishes the recording
+func (r *Recorder) Close() error {
+	fmt.Fprintf(r.w, "end %d\n", r.steps)
+	err := r.w.Flush()
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// A Replay feeds a recording back into the simulation in place of the joysticks
+type Replay struct {
+	Seed    int64
+	Rate    int
+	Players int // how many players the recording has input for
+	steps   map[int][]Input
+	end     int // how many steps were recorded
+}
+
+// loadReplay reads a recording made by a Recorder
+func loadReplay(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := &Replay{steps: make(map[int][]Input)}
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("%s: empty recording", path)
+	}
+	if _, err = fmt.Sscanf(scanner.Text(), "seed %d rate %d", &r.Seed, &r.Rate); err != nil {
+		return nil, fmt.Errorf("%s: bad header: %v", path, err)
+	}
+	for line := 2; scanner.Scan(); line++ {
+		if _, err = fmt.Sscanf(scanner.Text(), "end %d", &r.end); err == nil {
+			continue
+		}
+		var step int
+		var in Input
+		if _, err = fmt.Sscanf(scanner.Text(), "%d %d %d %d %d", &step, &in.Player, &in.Kind, &in.Index, &in.Value); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		r.steps[step] = append(r.steps[step], in)
+		if step >= r.end {
+			// a recording cut short ends after its last input
+			r.end = step + 1
+		}
+		if in.Player+1 > r.Players {
+			r.Players = in.Player + 1
+		}
+	}
+	return r, scanner.Err()
+}
+
+// Inputs gives the recorded inputs for a step
+func (r *Replay) Inputs(step int) []Input {
+	return r.steps[step]
+}
+
+// Done reports whether every recorded step has been replayed by the given step
+func (r *Replay) Done(step int) bool {
+	return step >= r.end
+}
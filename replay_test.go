@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	useConfig(t, Config{Seed: 42, UpdateRate: 60})
+	path := filepath.Join(t.TempDir(), "session.rec")
+	recorded := [][]Input{
+		{{Player: 0, Kind: INPUT_AXIS, Index: AXIS_LEFTX, Value: 20000}},
+		nil,
+		{{Player: 1, Kind: INPUT_BUTTON, Index: 2, Value: 1}, gameInput(GAME_TEACHER, TEACHER_ADVANCE)},
+		nil,
+		nil,
+	}
+	r, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for step, inputs := range recorded {
+		r.Record(step, inputs)
+	}
+	if err = r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := loadReplay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Seed != 42 || replay.Rate != 60 || replay.Players != 2 {
+		t.Errorf("replay has seed %d rate %d players %d, want 42 60 2", replay.Seed, replay.Rate, replay.Players)
+	}
+	for step, want := range recorded {
+		if replay.Done(step) {
+			t.Errorf("replay done at step %d of %d", step, len(recorded))
+		}
+		got := replay.Inputs(step)
+		if len(got) != len(want) {
+			t.Errorf("step %d has %d inputs, want %d", step, len(got), len(want))
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("step %d input %d is %+v, want %+v", step, i, got[i], want[i])
+			}
+		}
+	}
+	if !replay.Done(len(recorded)) {
+		t.Errorf("replay not done after all %d steps", len(recorded))
+	}
+}
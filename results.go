@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"github.com/jonhanks/Go-SDL/ttf"
+)
+
+// Results is the screen shown at the end of a race or a tracking exercise, a title and a line for
+// each player
+type Results struct {
+	lines []*Label
+}
+
+// Create the results screen for the game g has just finished
+func NewResults(font, bigFont *ttf.Font, g *Game) *Results {
+	var title string
+	var lines []string
+	if config.Mode == MODE_TRACK {
+		title, lines = trackResults(g)
+	} else {
+		title, lines = raceResults(g)
+	}
+	r := &Results{}
+	l := &Label{Font: bigFont, Color: sdl.Color{255, 255, 0, 0}}
+	l.SetText(title)
+	r.lines = append(r.lines, l)
+	for _, line := range lines {
+		l := &Label{Font: font, Color: config.TextColor}
+		l.SetText(line)
+		r.lines = append(r.lines, l)
+	}
+	// stack the lines in the middle of the screen
+	h := 0
+	for _, l := range r.lines {
+		h += int(l.Rect().H) + 10
+	}
+	y := (frameH - h) / 2
+	for _, l := range r.lines {
+		l.X, l.Y = (frameW-int(l.Rect().W))/2, y
+		y += int(l.Rect().H) + 10
+	}
+	return r
+}
+
+// Draw the results
+func (r *Results) Draw(screen *sdl.Surface) {
+	screen.FillRect(r.Rect(), config.BackgroundColor)
+	for _, l := range r.lines {
+		l.Draw(screen)
+	}
+}
+
+// Get the area the results cover
+func (r *Results) Rect() *sdl.Rect {
+	return frameRect()
+}
+
+// Free the results text
+func (r *Results) Free() {
+	for _, l := range r.lines {
+		l.Free()
+	}
+}
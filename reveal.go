@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"time"
+)
+
+// Goal reveal animations
+const (
+	REVEAL_NONE  = "none"  // the goal just appears
+	REVEAL_FADE  = "fade"  // the goal fades in
+	REVEAL_SCALE = "scale" // the goal grows from nothing
+	REVEAL_DROP  = "drop"  // the goal drops in from the top of the screen
+)
+
+// RevealProgress gives how far the current goal's reveal animation has got, from 0 to 1
+func (g *Game) RevealProgress() float64 {
+	if config.Mode == MODE_FREE {
+		// the goals are all on show from the start
+		return 1
+	}
+	return revealProgress(g.Clock-g.goalShown, config.RevealTime)
+}
+
+// revealProgress gives how far a reveal lasting length has got after elapsed
+func revealProgress(elapsed, length time.Duration) float64 {
+	if config.Reveal == REVEAL_NONE || length <= 0 || elapsed >= length {
+		return 1
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(elapsed) / float64(length)
+}
+
+// Can the current goal be collected yet.  Unless config.RevealCollide is set it has to be fully
+// revealed first.
+func (g *Game) Collectable() bool {
+	return config.RevealCollide || g.RevealProgress() >= 1
+}
+
+// A RevealingGoal is a Drawable showing a goal part way through its reveal animation
+type RevealingGoal struct {
+	Goal     *Goal
+	Progress float64 // 0 to 1
+}
+
+// Reveal wraps the goal in its reveal animation if the goal is the current one and still appearing
+func (g *Game) Reveal(goal *Goal) Drawable {
+	if goal != g.Current() {
+		return goal
+	}
+	if p := g.RevealProgress(); p < 1 {
+		return RevealingGoal{goal, p}
+	}
+	return goal
+}
+
+// Get the bounding rectangle of the goal once revealed
+func (r RevealingGoal) Rect() *sdl.Rect {
+	return r.Goal.Rect()
+}
+
+// DrawRect gives the part of the screen the reveal draws on, which for a drop is everything above the
+// goal too
+func (r RevealingGoal) DrawRect() *sdl.Rect {
+	rect := r.Goal.Rect()
+	if config.Reveal == REVEAL_DROP {
+		rect.H += uint16(rect.Y)
+		rect.Y = 0
+	}
+	return rect
+}
+
+// Draw the goal as it is at this point of the reveal
+func (r RevealingGoal) Draw(screen *sdl.Surface) {
+	g := r.Goal
+	if g.Hidden || g.Surface == nil {
+		return
+	}
+	switch config.Reveal {
+	case REVEAL_FADE:
+		if s := fadeSurface(g.Surface, r.Progress); s != nil {
+			screen.Blit(g.Rect(), s, nil)
+			s.Free()
+		}
+	case REVEAL_SCALE:
+		w, h := int(float64(g.W)*r.Progress), int(float64(g.H)*r.Progress)
+		if s := scaleSurface(g.Surface, w, h); s != nil {
+			screen.Blit(&sdl.Rect{int16(g.X - w/2), int16(g.Y - h/2), 0, 0}, s, nil)
+			s.Free()
+		}
+	case REVEAL_DROP:
+		// fall from just above the screen
+		start := -g.H / 2
+		y := start + int(float64(g.Y-start)*r.Progress)
+		screen.Blit(&sdl.Rect{int16(g.X - g.W/2), int16(y - g.H/2), 0, 0}, g.Surface, nil)
+	default:
+		g.Draw(screen)
+	}
+}
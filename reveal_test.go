@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRevealProgress(t *testing.T) {
+	tests := []struct {
+		reveal  string
+		elapsed time.Duration
+		want    float64
+	}{
+		{REVEAL_FADE, -time.Second, 0},
+		{REVEAL_FADE, 0, 0},
+		{REVEAL_FADE, 250 * time.Millisecond, 0.25},
+		{REVEAL_FADE, time.Second, 1},
+		{REVEAL_FADE, time.Hour, 1},
+		{REVEAL_NONE, 0, 1},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{Reveal: tt.reveal})
+		if got := revealProgress(tt.elapsed, time.Second); got != tt.want {
+			t.Errorf("%s after %v = %v, want %v", tt.reveal, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestCollectableOnceRevealed(t *testing.T) {
+	for _, collide := range []bool{false, true} {
+		useConfig(t, Config{Reveal: REVEAL_SCALE, RevealTime: time.Second, RevealCollide: collide, CollectButton: -1})
+		g := NewGame([]Marker{{}}, letterGoals("AB", nil))
+		Step(g, nil, 0) // the first goal appears
+		Step(g, nil, time.Second/2)
+		if got := g.Collectable(); got != collide {
+			t.Errorf("collide %v: collectable half way through the reveal is %v", collide, got)
+		}
+		Step(g, nil, time.Second/2)
+		if !g.Collectable() {
+			t.Errorf("collide %v: not collectable once revealed", collide)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// saveScreenshot saves the 32 bit surface s as a PNG named after the time in dir, making dir if it
+// is not there.  It gives the file saved.
+func saveScreenshot(s *sdl.Surface, dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	img := surfaceImage(s, 1)
+	path := filepath.Join(dir, "screenshot-"+now.Format("20060102-150405.000")+".png")
+	out, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err = png.Encode(out, img); err != nil {
+		out.Close()
+		return "", err
+	}
+	return path, out.Close()
+}
+
+// surfaceImage copies the 32 bit surface s to an image, keeping every shrink'th pixel each way
+func surfaceImage(s *sdl.Surface, shrink int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, int(s.W)/shrink, int(s.H)/shrink))
+	f := s.Format
+	s.Lock()
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			p := pixelAt(s, x*shrink, y*shrink)
+			img.SetRGBA(x, y, color.RGBA{uint8(channel(p, f.Rmask, f.Rshift)), uint8(channel(p, f.Gmask, f.Gshift)), uint8(channel(p, f.Bmask, f.Bshift)), 0xff})
+		}
+	}
+	s.Unlock()
+	return img
+}
+
+// takeScreenshot saves s to config.ScreenshotDir, reporting where
+func takeScreenshot(s *sdl.Surface) {
+	path, err := saveScreenshot(s, config.ScreenshotDir, time.Now())
+	if err != nil {
+		fmt.Println("cannot save screenshot:", err)
+		return
+	}
+	fmt.Println("saved a screenshot to", path)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"io"
+)
+
+// StickCaps describes what SDL reports about a joystick
+type StickCaps struct {
+	Name                       string
+	Axes, Buttons, Hats, Balls int
+}
+
+// queryCaps asks SDL what the opened joystick j provides
+func queryCaps(j *sdl.Joystick, name string) StickCaps {
+	if j == nil {
+		return StickCaps{Name: name}
+	}
+	return StickCaps{Name: name, Axes: j.NumAxes(), Buttons: j.NumButtons(), Hats: j.NumHats(), Balls: j.NumBalls()}
+}
+
+// capsWarnings lists anything about the joystick that will keep it from working well in the game
+func capsWarnings(c StickCaps) []string {
+	var warnings []string
+	if c.Axes == 0 && c.Hats == 0 {
+		warnings = append(warnings, "no axes or hats, the marker cannot be moved")
+	} else if c.Axes == 1 {
+		warnings = append(warnings, "only one axis, the marker can only move one way")
+	}
+	if c.Buttons == 0 {
+		warnings = append(warnings, "no buttons")
+	}
+	return warnings
+}
+
+// formatCaps builds the capability summary for joystick i
+func formatCaps(i int, c StickCaps) string {
+	s := fmt.Sprintf("joystick %d %q: %d axes, %d buttons, %d hats, %d balls\n", i+1, c.Name, c.Axes, c.Buttons, c.Hats, c.Balls)
+	for _, w := range capsWarnings(c) {
+		s += fmt.Sprintf("  warning: %s\n", w)
+	}
+	return s
+}
+
+// selfTest prints a capability summary of each opened joystick
+func selfTest(w io.Writer, sticks []*sdl.Joystick) {
+	fmt.Fprintln(w, "Joystick self test:")
+	for i, j := range sticks {
+		fmt.Fprint(w, formatCaps(i, queryCaps(j, sdl.JoystickName(i))))
+	}
+}
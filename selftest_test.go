@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCapsWarnings(t *testing.T) {
+	tests := []struct {
+		name string
+		caps StickCaps
+		want []string
+	}{
+		{"gamepad", StickCaps{Axes: 6, Buttons: 12, Hats: 1}, nil},
+		{"hat only", StickCaps{Buttons: 4, Hats: 1}, nil},
+		{"nothing to move with", StickCaps{Buttons: 2}, []string{"no axes or hats, the marker cannot be moved"}},
+		{"one axis", StickCaps{Axes: 1, Buttons: 2}, []string{"only one axis, the marker can only move one way"}},
+		{"no buttons", StickCaps{Axes: 2}, []string{"no buttons"}},
+	}
+	for _, tt := range tests {
+		if got := capsWarnings(tt.caps); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: warnings %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCaps(t *testing.T) {
+	s := formatCaps(0, StickCaps{Name: "Pad", Axes: 2})
+	if !strings.HasPrefix(s, `joystick 1 "Pad": 2 axes, 0 buttons, 0 hats, 0 balls`) || !strings.Contains(s, "warning: no buttons") {
+		t.Errorf("formatCaps gave %q", s)
+	}
+}
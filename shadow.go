@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// withShadow returns a new surface holding fg drawn over a copy of shadow offset by off pixels down
+// and to the right.  Both surfaces must be 32 bit with an alpha channel, as TTF's blended text is.
+// The result is off pixels wider and taller than fg.  SDL 1.2 does not keep the alpha channel when
+// blitting between two alpha surfaces, so the compositing is done here.
+func withShadow(fg, shadow *sdl.Surface, off int) *sdl.Surface {
+	f := fg.Format
+	w, h := int(fg.W)+off, int(fg.H)+off
+	out := newLike(fg, w, h)
+	if out == nil {
+		return nil
+	}
+	fg.Lock()
+	shadow.Lock()
+	out.Lock()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dst uint32
+			if x >= off && y >= off && x-off < int(shadow.W) && y-off < int(shadow.H) {
+				dst = pixelAt(shadow, x-off, y-off)
+			}
+			if x < int(fg.W) && y < int(fg.H) {
+				dst = over(pixelAt(fg, x, y), dst, f)
+			}
+			*pixelPtr(out, x, y) = dst
+		}
+	}
+	out.Unlock()
+	shadow.Unlock()
+	fg.Unlock()
+	return out
+}
+
+// channel extracts the 8 bit channel with the given mask and shift from p
+func channel(p, mask uint32, shift uint8) uint32 {
+	return (p & mask) >> shift
+}
+
+// over composites the pixel src over dst, both in format f
+func over(src, dst uint32, f *sdl.PixelFormat) uint32 {
+	sa := channel(src, f.Amask, f.Ashift)
+	da := channel(dst, f.Amask, f.Ashift)
+	oa := sa + da*(255-sa)/255
+	if oa == 0 {
+		return 0
+	}
+	mix := func(mask uint32, shift uint8) uint32 {
+		s, d := channel(src, mask, shift), channel(dst, mask, shift)
+		return ((s*sa + d*da*(255-sa)/255) / oa) << shift
+	}
+	return mix(f.Rmask, f.Rshift) | mix(f.Gmask, f.Gshift) | mix(f.Bmask, f.Bshift) | oa<<f.Ashift
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"testing"
+)
+
+func TestWithShadow(t *testing.T) {
+	const A = 0xff000000
+	surface := func(p uint32) *sdl.Surface {
+		s := sdl.CreateRGBSurface(sdl.SWSURFACE, 2, 2, 32, 0xff0000, 0xff00, 0xff, A)
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 2; x++ {
+				*pixelPtr(s, x, y) = p
+			}
+		}
+		return s
+	}
+	out := withShadow(surface(A|0xffffff), surface(A), 1)
+	if out == nil {
+		t.Fatal("withShadow returned nil")
+	}
+	if out.W != 3 || out.H != 3 {
+		t.Fatalf("shadowed surface is %dx%d, want 3x3", out.W, out.H)
+	}
+	tests := []struct {
+		x, y int
+		want uint32
+	}{
+		{0, 0, A | 0xffffff}, // text
+		{1, 1, A | 0xffffff}, // text over shadow
+		{2, 2, A},            // shadow
+		{2, 0, 0},            // neither
+	}
+	for _, tt := range tests {
+		if got := pixelAt(out, tt.x, tt.y); got != tt.want {
+			t.Errorf("pixel %d,%d = %08x, want %08x", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
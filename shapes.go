@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+	"math/rand"
+)
+
+// Shapes drawn for the shapes goal set
+const (
+	SHAPE_CIRCLE = "circle"
+	SHAPE_SQUARE = "square"
+	SHAPE_STAR   = "star"
+)
+
+// the shapes and colors that are combined to make the shape goals
+var (
+	shapes      = []string{SHAPE_CIRCLE, SHAPE_SQUARE, SHAPE_STAR}
+	shapeColors = []struct {
+		Name  string
+		Color uint32 // RRGGBB
+	}{
+		{"red", 0xff0000},
+		{"green", 0x00c000},
+		{"blue", 0x0040ff},
+		{"yellow", 0xffff00},
+	}
+)
+
+// Create a Goal drawn as a colored shape, as big as config.GoalSize at the frame's scale.  Its text
+// names the color and shape, for the prompt.
+func NewShapeGoal(shape, colorName string, color uint32, order int) *Goal {
+	g := &Goal{Text: colorName + " " + shape, Order: order}
+	size := scaled(config.GoalSize)
+	g.Surface = sdl.CreateRGBSurface(sdl.SWSURFACE|sdl.SRCALPHA, size, size, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0xff000000)
+	g.Surface.Lock()
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			// the middle of the pixel, from -1 to 1 across the surface
+			px := (float64(x)+0.5)/float64(size)*2 - 1
+			py := (float64(y)+0.5)/float64(size)*2 - 1
+			p := uint32(0)
+			if inShape(shape, px, py) {
+				p = 0xff000000 | color
+			}
+			*pixelPtr(g.Surface, x, y) = p
+		}
+	}
+	g.Surface.Unlock()
+	g.W, g.H = size, size
+	return g
+}
+
+// inShape reports whether x, y (each from -1 to 1) is inside the shape
+func inShape(shape string, x, y float64) bool {
+	switch shape {
+	case SHAPE_CIRCLE:
+		return x*x+y*y <= 1
+	case SHAPE_SQUARE:
+		return math.Abs(x) <= 0.85 && math.Abs(y) <= 0.85
+	case SHAPE_STAR:
+		return inPolygon(starPoints, x, y)
+	}
+	return false
+}
+
+// the corners of a five pointed star with its top point straight up
+var starPoints = func() [][2]float64 {
+	var pts [][2]float64
+	for i := 0; i < 10; i++ {
+		r := 1.0
+		if i%2 == 1 {
+			r = 0.4
+		}
+		a := -math.Pi/2 + float64(i)*math.Pi/5
+		pts = append(pts, [2]float64{r * math.Cos(a), r * math.Sin(a)})
+	}
+	return pts
+}()
+
+// inPolygon reports whether x, y is inside the polygon with the given corners
+func inPolygon(pts [][2]float64, x, y float64) bool {
+	in := false
+	for i, j := 0, len(pts)-1; i < len(pts); j, i = i, i+1 {
+		a, b := pts[i], pts[j]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+// shapeGoals builds a goal for every color of every shape, in a random order and placed at random
+func shapeGoals(rng *rand.Rand) []*Goal {
+	var goals []*Goal
+	for _, c := range shapeColors {
+		for _, shape := range shapes {
+			goals = append(goals, NewShapeGoal(shape, c.Name, c.Color, 0))
+		}
+	}
+	rng.Shuffle(len(goals), func(i, j int) { goals[i], goals[j] = goals[j], goals[i] })
+	var recent placeHistory
+	for i, goal := range goals {
+		goal.Order = i
+		placeRandom(goal, &recent, rng)
+		goal.Hidden = false
+	}
+	return goals
+}
+
+// ShapePrompt names the shape to collect next, or "" when not collecting shapes
+func (g *Game) ShapePrompt() string {
+	goal := g.Current()
+	if config.GoalSet != GOALS_SHAPES || config.Mode == MODE_FREE || goal == nil || g.Won {
+		return ""
+	}
+	return "Find the " + goal.Text
+}
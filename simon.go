@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// how many goals the sequences are made from
+	SIMON_POOL = 6
+	// how long the first sequence is
+	SIMON_START = 2
+	// how long each goal in the sequence lights up for, and the gap before the next one
+	SIMON_FLASH = 700 * time.Millisecond
+	SIMON_GAP   = 300 * time.Millisecond
+	// pause before the sequence is shown
+	SIMON_WAIT = time.Second
+	// the color goals light up in
+	SIMON_COLOR = 0x00ffff00
+)
+
+// Simon is the state of the simon-says memory game.  A sequence of goals lights up one at a time and
+// the players then have to visit them in the same order.  Every time they manage it the sequence
+// gets one longer.
+type Simon struct {
+	Seq      []*Goal
+	At       int           // how far through Seq the players have got
+	showFrom time.Duration // when the sequence starts being shown
+}
+
+// startSimon picks the goals the sequences are made from and the first sequence
+func (g *Game) startSimon() {
+	g.rng.Shuffle(len(g.Goals), func(i, j int) { g.Goals[i], g.Goals[j] = g.Goals[j], g.Goals[i] })
+	g.simon = &Simon{showFrom: g.Clock + SIMON_WAIT}
+	for len(g.simon.Seq) < SIMON_START {
+		g.growSimon()
+	}
+}
+
+// simonPool gives the goals the sequences are made from
+func (g *Game) simonPool() []*Goal {
+	if len(g.Goals) > SIMON_POOL {
+		return g.Goals[:SIMON_POOL]
+	}
+	return g.Goals
+}
+
+// growSimon adds a random goal to the end of the sequence, never the same one twice running
+func (g *Game) growSimon() {
+	s, pool := g.simon, g.simonPool()
+	goal := pool[g.rng.Intn(len(pool))]
+	for len(pool) > 1 && len(s.Seq) > 0 && goal == s.Seq[len(s.Seq)-1] {
+		goal = pool[g.rng.Intn(len(pool))]
+	}
+	s.Seq = append(s.Seq, goal)
+	g.goalsMade(pool, len(s.Seq))
+}
+
+// simonShowing reports whether the sequence is being shown (or about to be), when the players have to
+// watch
+func (g *Game) simonShowing() bool {
+	s := g.simon
+	return s != nil && g.Clock < s.showFrom+time.Duration(len(s.Seq))*(SIMON_FLASH+SIMON_GAP)
+}
+
+// updateSimon checks the goals the players touch against the sequence
+func (g *Game) updateSimon() {
+	if g.simon == nil {
+		g.startSimon()
+	}
+	if g.simonShowing() {
+		return
+	}
+	for i := range g.Markers {
+		if g.simonTouch(i, &g.Markers[i]) || i < len(g.Hands) && g.simonTouch(i, &g.Hands[i]) {
+			return
+		}
+	}
+}
+
+// simonTouch handles player's marker m touching a goal, by settling on it or pressing the collect
+// button while settled.  Without a collect button a goal counts once each time the marker arrives on
+// it.  It returns true if it counted.
+func (g *Game) simonTouch(player int, m *Marker) bool {
+	s := g.simon
+	goal := m.dwellGoal
+	if goal == nil || !m.Settled(goal) || !g.Markers[player].CanCollect() {
+		return false
+	}
+	if config.CollectButton < 0 && m.touched == goal {
+		return false
+	}
+	m.touched = goal
+	if goal != s.Seq[s.At] {
+		// start again from the beginning, after seeing the sequence again
+		g.wrongGoal, g.wrongUntil = goal, g.Clock+WRONG_FLASH_TIME
+		g.event("wrong " + goal.Text)
+		s.At = 0
+		s.showFrom = g.Clock + SIMON_WAIT
+		return true
+	}
+	g.Markers[player].Score += GOAL_POINTS
+	g.rumble(player)
+	g.Collected++
+	if g.OnCollect != nil {
+		g.OnCollect(goal, len(s.Seq))
+	}
+	g.event("collect " + goal.Text)
+	g.burst(goal)
+	if s.At++; s.At == len(s.Seq) {
+		g.Celebrate(true)
+		g.event("round")
+		g.growSimon()
+		s.At = 0
+		s.showFrom = g.Clock + BIG_CELEBRATION*CELEBRATE_TIME
+	}
+	return true
+}
+
+// SimonFlash lights up the goal of the sequence being shown right now, or gives nil
+func (g *Game) SimonFlash() Drawable {
+	s := g.simon
+	if s == nil || !g.simonShowing() || g.Clock < s.showFrom {
+		return nil
+	}
+	since := g.Clock - s.showFrom
+	i := int(since / (SIMON_FLASH + SIMON_GAP))
+	if since%(SIMON_FLASH+SIMON_GAP) >= SIMON_FLASH {
+		return nil
+	}
+	return Outline{R: *s.Seq[i].Rect(), Color: SIMON_COLOR, Width: 6}
+}
+
+// simonText tells the players whether to watch or go, and how long the sequence is
+func simonText(g *Game) string {
+	if g.simon == nil {
+		return ""
+	}
+	if g.simonShowing() {
+		return fmt.Sprintf("Watch the %d goals", len(g.simon.Seq))
+	}
+	return fmt.Sprintf("Your turn: %d of %d", g.simon.At, len(g.simon.Seq))
+}
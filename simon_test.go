@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSimonTouch(t *testing.T) {
+	// each update in turn, whether the collect button is pressed and the At and Collected wanted after
+	type update struct {
+		press         bool
+		wantAt        int
+		wantCollected int
+	}
+	tests := []struct {
+		name          string
+		dwell         int
+		collectButton int
+		updates       []update
+	}{
+		{"dwell", 2, -1, []update{{false, 0, 0}, {false, 1, 1}, {false, 1, 1}, {false, 1, 1}}},
+		{"no dwell", 0, -1, []update{{false, 1, 1}, {false, 1, 1}}},
+		// the press only counts once the marker has settled, however long that took
+		{"collect button", 2, 0, []update{{true, 0, 0}, {false, 0, 0}, {false, 0, 0}, {true, 1, 1}, {false, 1, 1}}},
+		{"collect button no dwell", 0, 0, []update{{false, 0, 0}, {true, 1, 1}}},
+	}
+	for _, tt := range tests {
+		useConfig(t, Config{Mode: MODE_SIMON, Dwell: tt.dwell, CollectButton: tt.collectButton})
+		g := NewGame([]Marker{{}}, letterGoals("ABCD", nil))
+		g.startSimon()
+		s := g.simon
+		g.Clock = s.showFrom + SIMON_WAIT*10
+		m := &g.Markers[0]
+		m.X, m.Y = s.Seq[0].X, s.Seq[0].Y
+		for i, u := range tt.updates {
+			g.updateDwell()
+			if u.press {
+				m.Collect(true)
+				m.Collect(false)
+			}
+			g.updateSimon()
+			g.forgetPresses()
+			if s.At != u.wantAt || g.Collected != u.wantCollected {
+				t.Errorf("%s, update %d: at %d collected %d, want %d %d", tt.name, i, s.At, g.Collected, u.wantAt, u.wantCollected)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+// Sprites are the images drawn for markers instead of plain squares.  They come from the assets by
+// name and are converted to the display's format, which gives them the 32 bits scaling needs.
+type Sprites struct {
+	assets *Assets
+	loaded map[string]*sdl.Surface
+}
+
+// NewSprites gives the sprites found in assets.  The video mode has to be set before they are loaded.
+func NewSprites(assets *Assets) *Sprites {
+	return &Sprites{assets: assets, loaded: make(map[string]*sdl.Surface)}
+}
+
+// Get gives the named sprite, or nil if there is no such image
+func (s *Sprites) Get(name string) *sdl.Surface {
+	if name == "" {
+		return nil
+	}
+	if sprite, ok := s.loaded[name]; ok {
+		return sprite
+	}
+	var sprite *sdl.Surface
+	if img := s.assets.Image(name); img != nil {
+		sprite = img.DisplayFormatAlpha()
+	}
+	s.loaded[name] = sprite
+	return sprite
+}
+
+// Free releases the converted images
+func (s *Sprites) Free() {
+	for name, sprite := range s.loaded {
+		if sprite != nil {
+			sprite.Free()
+		}
+		delete(s.loaded, name)
+	}
+}
+
+// drawSprite draws the marker's sprite stretched over r
+func (m Marker) drawSprite(screen *sdl.Surface, r *sdl.Rect) {
+	if int(r.W) == int(m.Sprite.W) && int(r.H) == int(m.Sprite.H) {
+		screen.Blit(r, m.Sprite, nil)
+		return
+	}
+	if s := scaleSurface(m.Sprite, int(r.W), int(r.H)); s != nil {
+		screen.Blit(r, s, nil)
+		s.Free()
+	}
+}
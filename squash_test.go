@@ -0,0 +1,29 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStretchFactor(t *testing.T) {
+	tests := []struct {
+		vx, vy    float32
+		magnitude float64
+		sx, sy    float64
+	}{
+		{0, 0, 0.5, 1, 1},
+		{FULL_SPEED, 0, 0.5, 1.5, 1 / 1.5},
+		{0, -FULL_SPEED, 0.5, 1 / 1.5, 1.5},
+		{FULL_SPEED / 2, 0, 1, 1.5, 1 / 1.5},
+		{FULL_SPEED * 4, 0, 1, 2, 0.5},
+	}
+	for _, tt := range tests {
+		sx, sy := stretchFactor(tt.vx, tt.vy, tt.magnitude)
+		if math.Abs(sx-tt.sx) > 1e-6 || math.Abs(sy-tt.sy) > 1e-6 {
+			t.Errorf("stretchFactor(%v, %v, %v) = %v, %v, want %v, %v", tt.vx, tt.vy, tt.magnitude, sx, sy, tt.sx, tt.sy)
+		}
+		if math.Abs(sx*sy-1) > 1e-6 {
+			t.Errorf("stretchFactor(%v, %v, %v) changes the area", tt.vx, tt.vy, tt.magnitude)
+		}
+	}
+}
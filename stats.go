@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// printSummary writes a short human readable summary of the session for each player
+func printSummary(w io.Writer, g *Game) {
+	fmt.Fprintln(w, "Session summary:")
+	for i, m := range g.Markers {
+		fmt.Fprintf(w, "  player %d: scored %d, travelled %.0f pixels\n", i+1, m.Score, m.Distance)
+	}
+	if config.Mode == MODE_WHACK {
+		fmt.Fprintf(w, "  %d hits, %d misses\n", g.Collected, g.Misses)
+	}
+	if config.Mode == MODE_TRACK {
+		for i := range g.Markers {
+			fmt.Fprintf(w, "  player %d: on target %.1f%% of the time, %.1f pixels off on average\n", i+1, g.track.Accuracy(i), g.track.MeanError(i))
+		}
+	}
+}
+
+// writeStatsCSV writes the per player statistics to the CSV file at path, one row per player
+func writeStatsCSV(path string, markers []Marker) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"player", "score", "distance"})
+	for i, m := range markers {
+		w.Write([]string{strconv.Itoa(i + 1), strconv.Itoa(m.Score), strconv.FormatFloat(m.Distance, 'f', 1, 64)})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	// with a speed of 10 the hat moves the marker 4 pixels an update
+	useConfig(t, Config{Speed: 10})
+	tests := []struct {
+		name    string
+		x       int
+		updates int
+		wantX   int
+		want    float64
+	}{
+		{"still", 100, 0, 100, 0},
+		{"one move", 100, 1, 104, 4},
+		{"several moves", 100, 5, 120, 20},
+		{"wrap around the edge", WIDTH - 2, 1, 2, 4},
+		{"wrap then keep going", WIDTH - 2, 3, 10, 12},
+	}
+	for _, tt := range tests {
+		m := Marker{X: tt.x, Y: 100}
+		m.Hat(sdl.HAT_RIGHT)
+		for i := 0; i < tt.updates; i++ {
+			m.Update()
+		}
+		if m.X != tt.wantX {
+			t.Errorf("%s: x = %d, want %d", tt.name, m.X, tt.wantX)
+		}
+		if m.Distance != tt.want {
+			t.Errorf("%s: distance = %v, want %v", tt.name, m.Distance, tt.want)
+		}
+	}
+}
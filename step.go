@@ -0,0 +1,114 @@
+package main
+
+import (
+	"time"
+)
+
+// Kinds of Input
+const (
+	INPUT_AXIS = iota
+	INPUT_BUTTON
+	INPUT_HAT
+	INPUT_POSITION // the player is put at a position, from the mouse
+	INPUT_COLLECT  // the collect button, 1 for a press and 0 for a release
+	INPUT_GAME     // something done to the whole game, Index is one of the GAME_* constants
+)
+
+// What an INPUT_GAME input does.  Everything that changes the game goes through Step, so recordings
+// replay the same.
+const (
+	GAME_PAUSE     = iota // pause or unpause
+	GAME_MENU             // pick the pause menu choice numbered Value
+	GAME_IDLE             // nobody has played for a while
+	GAME_WAKE             // somebody came back
+	GAME_TEACHER          // the teacher override action Value
+	GAME_TEST_MODE        // turn assists off or on
+)
+
+// An Input is one piece of joystick input for a simulation step, already routed to a player
+type Input struct {
+	Player int   // ignored for INPUT_GAME
+	Kind   int   // one of the INPUT_* constants
+	Index  int   // which axis or button, or 0 for x and 1 for y positions
+	Value  int16 // the axis, hat or world position, or 1 for a button press and 0 for a release
+}
+
+// Step advances the game by one fixed time step of length dt, after applying the inputs gathered for
+// it.  Step never looks at the wall clock and all randomness comes from the game's own seeded source,
+// so the same starting state and the same inputs always lead to the same state.
+func Step(g *Game, inputs []Input, dt time.Duration) {
+	for _, in := range inputs {
+		g.apply(in)
+	}
+	if !g.Paused {
+		// the clock stops while the game is paused, so timeouts and speed bonuses wait too
+		g.Clock += dt
+	}
+	g.Update()
+}
+
+// apply an input to the player it was routed to
+func (g *Game) apply(in Input) {
+	if in.Kind == INPUT_GAME {
+		g.applyGame(in.Index, int(in.Value))
+		return
+	}
+	if in.Player < 0 || in.Player >= len(g.Markers) {
+		return
+	}
+	m := &g.Markers[in.Player]
+	switch in.Kind {
+	case INPUT_AXIS:
+		if (in.Index == AXIS_RIGHTX || in.Index == AXIS_RIGHTY) && in.Player < len(g.Hands) {
+			// the right stick drives the player's second marker like a left stick
+			g.Hands[in.Player].Axis(in.Index-AXIS_RIGHTX, in.Value)
+		} else {
+			m.Axis(in.Index, in.Value)
+		}
+	case INPUT_BUTTON:
+		if config.Mode == MODE_PAINT && g.paintButton(in.Player, in.Index, in.Value != 0) {
+			return
+		}
+		m.Button(in.Index, in.Value != 0)
+		if g.Finished && in.Value != 0 {
+			g.PlayAgain()
+		}
+		if g.Editing && in.Value != 0 {
+			g.SelectNext()
+		}
+	case INPUT_HAT:
+		m.Hat(uint8(in.Value))
+	case INPUT_COLLECT:
+		m.Collect(in.Value != 0)
+		if g.Finished && in.Value != 0 {
+			g.PlayAgain()
+		}
+	case INPUT_POSITION:
+		m.MoveTo(in.Index, int(in.Value))
+	}
+}
+
+// applyGame does an INPUT_GAME input
+func (g *Game) applyGame(what, value int) {
+	switch what {
+	case GAME_PAUSE:
+		g.TogglePause()
+	case GAME_MENU:
+		if value >= 0 && value < len(pauseChoices) {
+			g.Resume(pauseChoices[value])
+		}
+	case GAME_IDLE:
+		g.GoIdle()
+	case GAME_WAKE:
+		g.Wake()
+	case GAME_TEACHER:
+		g.Override(value)
+	case GAME_TEST_MODE:
+		g.ToggleTestMode()
+	}
+}
+
+// gameInput makes the input that does what to the whole game
+func gameInput(what, value int) Input {
+	return Input{Kind: INPUT_GAME, Index: what, Value: int16(value)}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// snapshot is the part of a game's state that should come out the same from the same inputs
+type snapshot struct {
+	Clock     time.Duration
+	CurGoal   int
+	Collected int
+	Paused    bool
+	Markers   [][3]int // x, y and score
+	Goals     [][2]int
+}
+
+func snap(g *Game) snapshot {
+	s := snapshot{Clock: g.Clock, CurGoal: g.CurGoal, Collected: g.Collected, Paused: g.Paused}
+	for _, m := range g.Markers {
+		s.Markers = append(s.Markers, [3]int{m.X, m.Y, m.Score})
+	}
+	for _, goal := range g.Goals {
+		s.Goals = append(s.Goals, [2]int{goal.X, goal.Y})
+	}
+	return s
+}
+
+// play runs a game for steps steps, feeding it random inputs made from seed
+func play(seed int64, steps int) snapshot {
+	g := NewGame([]Marker{newMarker(0, 2), newMarker(1, 2)}, letterGoals("ABCDE", rand.New(rand.NewSource(1))))
+	input := rand.New(rand.NewSource(seed))
+	for i := 0; i < steps; i++ {
+		var inputs []Input
+		switch input.Intn(6) {
+		case 0, 1:
+			inputs = append(inputs, Input{Player: input.Intn(2), Kind: INPUT_AXIS, Index: input.Intn(2), Value: int16(input.Intn(65536) - 32768)})
+		case 2:
+			inputs = append(inputs, Input{Player: input.Intn(2), Kind: INPUT_BUTTON, Index: 0, Value: int16(input.Intn(2))})
+		case 3:
+			inputs = append(inputs, gameInput(GAME_TEACHER, TEACHER_ADVANCE))
+		case 4:
+			if input.Intn(20) == 0 {
+				inputs = append(inputs, gameInput(GAME_PAUSE, 0))
+			}
+		}
+		Step(g, inputs, time.Second/60)
+	}
+	return snap(g)
+}
+
+func TestStepDeterministic(t *testing.T) {
+	useConfig(t, Config{Seed: 7, CollectButton: -1, EndPolicy: END_NEXT})
+	for _, seed := range []int64{1, 2, 3} {
+		a, b := play(seed, 600), play(seed, 600)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("seed %d: the same inputs gave %+v and %+v", seed, a, b)
+		}
+	}
+	if reflect.DeepEqual(play(1, 600), play(2, 600)) {
+		t.Errorf("different inputs gave the same game")
+	}
+}
+
+func TestGameInputs(t *testing.T) {
+	useConfig(t, Config{CollectButton: -1})
+	g := NewGame([]Marker{{}}, letterGoals("ABC", nil))
+	Step(g, []Input{gameInput(GAME_PAUSE, 0)}, time.Second)
+	if !g.Paused || g.Clock != 0 {
+		t.Errorf("pausing left paused %v with the clock at %v", g.Paused, g.Clock)
+	}
+	Step(g, []Input{gameInput(GAME_TEACHER, TEACHER_ADVANCE)}, time.Second)
+	Step(g, []Input{gameInput(GAME_MENU, 0)}, time.Second)
+	if g.Paused || g.CurGoal != 1 {
+		t.Errorf("resuming left paused %v at goal %d, want false 1", g.Paused, g.CurGoal)
+	}
+	Step(g, []Input{gameInput(GAME_IDLE, 0)}, time.Second)
+	Step(g, []Input{gameInput(GAME_WAKE, 0)}, time.Second)
+	if g.Idle || g.CurGoal != 0 {
+		t.Errorf("waking left idle %v at goal %d, want a fresh round", g.Idle, g.CurGoal)
+	}
+}
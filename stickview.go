@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+const (
+	// size of the stick box of a stick view, the rest is laid out around it
+	STICKVIEW_SIZE = 80
+	// how many buttons a stick view shows
+	STICKVIEW_BUTTONS = 16
+	// size of a button light
+	STICKVIEW_BUTTON = 8
+	// room around each part of a stick view
+	STICKVIEW_GAP = 6
+	// width of a whole stick view, the button lights in a row, and the height above them and in all
+	STICKVIEW_W       = STICKVIEW_SIZE + 3*STICKVIEW_GAP + 2*STICKVIEW_BUTTON + STICKVIEW_SIZE/2
+	STICKVIEW_PER_ROW = STICKVIEW_W / (STICKVIEW_BUTTON + 2)
+	STICKVIEW_TOP     = STICKVIEW_SIZE + 2*STICKVIEW_GAP
+	STICKVIEW_H       = STICKVIEW_TOP + (STICKVIEW_BUTTONS+STICKVIEW_PER_ROW-1)/STICKVIEW_PER_ROW*(STICKVIEW_BUTTON+2) + STICKVIEW_GAP
+
+	STICKVIEW_BACK  = uint32(0x00101010)
+	STICKVIEW_FRAME = uint32(0x00606060)
+	STICKVIEW_LIT   = uint32(0x00ffff00)
+)
+
+// A StickView shows what a player's controller is doing: where the stick is, which way the hat
+// points, how far the triggers are squeezed and which buttons are held.  F11 shows one for each
+// player along the bottom of the screen, for checking a controller works and is mapped right.
+type StickView struct {
+	Marker *Marker
+	Color  uint32 // the player's color, for the stick's dot
+	X, Y   int
+}
+
+// StickViews gives a view for each player, in a row above the HUD
+func StickViews(g *Game) []StickView {
+	views := make([]StickView, len(g.Markers))
+	for i := range g.Markers {
+		views[i] = StickView{Marker: &g.Markers[i], Color: g.Markers[i].Color, X: 10 + i*(STICKVIEW_W+10), Y: frameH - HUD_HEIGHT - STICKVIEW_H - 10}
+	}
+	return views
+}
+
+// Get the box the view covers
+func (v StickView) Rect() *sdl.Rect {
+	return &sdl.Rect{int16(v.X), int16(v.Y), STICKVIEW_W, STICKVIEW_H}
+}
+
+// Draw the view
+func (v StickView) Draw(screen *sdl.Surface) {
+	m := v.Marker
+	screen.FillRect(v.Rect(), STICKVIEW_BACK)
+	fill := func(x, y, w, h int, color uint32) {
+		screen.FillRect(&sdl.Rect{int16(v.X + x), int16(v.Y + y), uint16(w), uint16(h)}, color)
+	}
+
+	// the stick, the axis values run from -0.5 to 0.5
+	g := STICKVIEW_GAP
+	Outline{R: sdl.Rect{int16(v.X + g), int16(v.Y + g), STICKVIEW_SIZE, STICKVIEW_SIZE}, Color: STICKVIEW_FRAME, Width: 1}.Draw(screen)
+	fill(g+STICKVIEW_SIZE/2, g, 1, STICKVIEW_SIZE, STICKVIEW_FRAME)
+	fill(g, g+STICKVIEW_SIZE/2, STICKVIEW_SIZE, 1, STICKVIEW_FRAME)
+	sx := g + STICKVIEW_SIZE/2 + int(m.targetX*STICKVIEW_SIZE)
+	sy := g + STICKVIEW_SIZE/2 + int(m.targetY*STICKVIEW_SIZE)
+	fill(sx-4, sy-4, 8, 8, v.Color)
+
+	// the triggers, as bars filling upwards
+	for i, t := range m.Triggers {
+		x := 2*g + STICKVIEW_SIZE + i*(STICKVIEW_BUTTON+2)
+		fill(x, g, STICKVIEW_BUTTON, STICKVIEW_SIZE, STICKVIEW_FRAME)
+		h := int(t * STICKVIEW_SIZE)
+		fill(x, g+STICKVIEW_SIZE-h, STICKVIEW_BUTTON, h, STICKVIEW_LIT)
+	}
+
+	// the hat, as a 3x3 grid with the direction it points in lit
+	cell := STICKVIEW_SIZE / 6
+	hx := 3*g + STICKVIEW_SIZE + 2*STICKVIEW_BUTTON
+	hy := g + STICKVIEW_SIZE/2 - 3*cell/2
+	for r := -1; r <= 1; r++ {
+		for c := -1; c <= 1; c++ {
+			color := STICKVIEW_FRAME
+			if float32(c) == m.Vhx && float32(r) == m.Vhy && (r != 0 || c != 0) {
+				color = STICKVIEW_LIT
+			}
+			fill(hx+(c+1)*cell+1, hy+(r+1)*cell+1, cell-2, cell-2, color)
+		}
+	}
+
+	// the buttons, in rows under the rest
+	for b := 0; b < STICKVIEW_BUTTONS; b++ {
+		color := STICKVIEW_FRAME
+		if m.ButtonHeld(b) {
+			color = STICKVIEW_LIT
+		}
+		row, col := b/STICKVIEW_PER_ROW, b%STICKVIEW_PER_ROW
+		fill(2+col*(STICKVIEW_BUTTON+2), STICKVIEW_TOP+row*(STICKVIEW_BUTTON+2), STICKVIEW_BUTTON, STICKVIEW_BUTTON, color)
+	}
+}
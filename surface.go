@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+	"unsafe"
+)
+
+// pixelPtr points at the pixel at x, y of a locked 32 bit surface
+func pixelPtr(s *sdl.Surface, x, y int) *uint32 {
+	return (*uint32)(unsafe.Pointer(uintptr(s.Pixels) + uintptr(y*int(s.Pitch)+x*4)))
+}
+
+// pixelAt reads the pixel at x, y of a locked 32 bit surface
+func pixelAt(s *sdl.Surface, x, y int) uint32 {
+	return *pixelPtr(s, x, y)
+}
+
+// newLike creates an empty surface of size w x h with the same 32 bit pixel format as s
+func newLike(s *sdl.Surface, w, h int) *sdl.Surface {
+	f := s.Format
+	return sdl.CreateRGBSurface(sdl.SWSURFACE|sdl.SRCALPHA, w, h, 32, f.Rmask, f.Gmask, f.Bmask, f.Amask)
+}
+
+// scaleSurface returns a copy of the 32 bit surface s scaled to w x h, nearest neighbour.  SDL 1.2
+// has no scaling of its own.
+func scaleSurface(s *sdl.Surface, w, h int) *sdl.Surface {
+	if w < 1 || h < 1 {
+		return nil
+	}
+	out := newLike(s, w, h)
+	if out == nil {
+		return nil
+	}
+	s.Lock()
+	out.Lock()
+	for y := 0; y < h; y++ {
+		sy := y * int(s.H) / h
+		for x := 0; x < w; x++ {
+			*pixelPtr(out, x, y) = pixelAt(s, x*int(s.W)/w, sy)
+		}
+	}
+	out.Unlock()
+	s.Unlock()
+	return out
+}
+
+// fadeSurface returns a copy of the 32 bit alpha surface s with its alpha scaled by alpha (0 to 1).
+// SDL 1.2 ignores the per surface alpha of surfaces with an alpha channel, so this is done per pixel.
+func fadeSurface(s *sdl.Surface, alpha float64) *sdl.Surface {
+	out := newLike(s, int(s.W), int(s.H))
+	if out == nil {
+		return nil
+	}
+	f := s.Format
+	s.Lock()
+	out.Lock()
+	for y := 0; y < int(s.H); y++ {
+		for x := 0; x < int(s.W); x++ {
+			p := pixelAt(s, x, y)
+			a := uint32(float64(channel(p, f.Amask, f.Ashift)) * alpha)
+			*pixelPtr(out, x, y) = p&^f.Amask | a<<f.Ashift
+		}
+	}
+	out.Unlock()
+	s.Unlock()
+	return out
+}
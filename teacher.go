@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Teacher override actions
+const (
+	TEACHER_NONE = iota
+	TEACHER_ADVANCE
+	TEACHER_RESTART
+	TEACHER_CELEBRATE
+)
+
+// TeacherKeys maps keyboard keys to the override actions a teacher can use to guide a session,
+// whatever the players are doing with their joysticks.
+type TeacherKeys map[uint32]int
+
+// newTeacherKeys resolves the configured key names.  Unknown names and keys the game already uses are
+// reported and skipped.
+func newTeacherKeys() TeacherKeys {
+	keys := make(TeacherKeys)
+	if !config.TeacherKeys {
+		return keys
+	}
+	for name, action := range map[string]int{
+		config.TeacherAdvance:   TEACHER_ADVANCE,
+		config.TeacherRestart:   TEACHER_RESTART,
+		config.TeacherCelebrate: TEACHER_CELEBRATE,
+	} {
+		if name == "" {
+			continue
+		}
+		if k, ok := lookupKey(name); ok && builtinKeys[k] != "" {
+			fmt.Printf("teacher key %q is already used for %s, pick another\n", name, builtinKeys[k])
+		} else if ok {
+			keys[k] = action
+		} else {
+			fmt.Printf("unknown teacher key %q\n", name)
+		}
+	}
+	return keys
+}
+
+// Action returns the override action bound to key sym, or TEACHER_NONE
+func (t TeacherKeys) Action(sym uint32) int {
+	return t[sym]
+}
+
+// Override performs a teacher override action
+func (g *Game) Override(action int) {
+	switch action {
+	case TEACHER_ADVANCE:
+		g.advance()
+	case TEACHER_RESTART:
+		g.Restart()
+	case TEACHER_CELEBRATE:
+		g.Celebrate(false)
+	}
+}
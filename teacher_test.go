@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTeacherOverride(t *testing.T) {
+	useConfig(t, Config{EndPolicy: END_LOOP, CollectButton: -1})
+	tests := []struct {
+		name          string
+		actions       []int
+		wantGoal      int
+		wantCelebrate bool
+	}{
+		{"nothing", []int{TEACHER_NONE}, 0, false},
+		{"advance", []int{TEACHER_ADVANCE}, 1, false},
+		{"advance twice", []int{TEACHER_ADVANCE, TEACHER_ADVANCE}, 2, false},
+		{"advance past the end loops", []int{TEACHER_ADVANCE, TEACHER_ADVANCE, TEACHER_ADVANCE}, 0, false},
+		{"restart", []int{TEACHER_ADVANCE, TEACHER_RESTART}, 0, false},
+		{"celebrate", []int{TEACHER_CELEBRATE}, 0, true},
+	}
+	for _, tt := range tests {
+		// the marker is far from every goal, so nothing is collected
+		g := NewGame([]Marker{{X: 10, Y: 10}}, letterGoals("ABC", nil))
+		g.Update()
+		for _, a := range tt.actions {
+			g.Override(a)
+		}
+		if g.CurGoal != tt.wantGoal || g.Celebrating() != tt.wantCelebrate {
+			t.Errorf("%s: goal %d celebrating %v, want %d and %v", tt.name, g.CurGoal, g.Celebrating(), tt.wantGoal, tt.wantCelebrate)
+		}
+		if g.Collected != 0 {
+			t.Errorf("%s: %d goals collected, want none", tt.name, g.Collected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"os"
+	"path/filepath"
+)
+
+// A Theme sets how the game looks.  Colors are written as RRGGBB, anything left out keeps the
+// default look.
+type Theme struct {
+	Background string   `json:"background,omitempty"` // the screen behind everything
+	Markers    []string `json:"markers,omitempty"`    // the first players' marker colors, in order
+	Goal       string   `json:"goal,omitempty"`       // the goal text
+	Text       string   `json:"text,omitempty"`       // the labels, menus and HUD
+	Font       string   `json:"font,omitempty"`       // font file, relative to the theme file
+}
+
+// loadTheme reads a theme file and puts its look into config
+func loadTheme(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var t Theme
+	if err = json.Unmarshal(data, &t); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if err = t.apply(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// apply puts the theme's look into config, dir is where the theme file is
+func (t *Theme) apply(dir string) error {
+	if t.Background != "" {
+		c, err := parseColor(t.Background)
+		if err != nil {
+			return err
+		}
+		config.BackgroundColor = packColor(c)
+	}
+	for _, s := range t.Markers {
+		c, err := parseColor(s)
+		if err != nil {
+			return err
+		}
+		config.MarkerColors = append(config.MarkerColors, packColor(c))
+	}
+	var err error
+	if t.Goal != "" {
+		if config.GoalColor, err = parseColor(t.Goal); err != nil {
+			return err
+		}
+	}
+	if t.Text != "" {
+		if config.TextColor, err = parseColor(t.Text); err != nil {
+			return err
+		}
+	}
+	if t.Font != "" {
+		config.Font = t.Font
+		if !filepath.IsAbs(t.Font) {
+			config.Font = filepath.Join(dir, t.Font)
+		}
+	}
+	return nil
+}
+
+// packColor gives a color as the 0x00RRGGBB the screen is filled with
+func packColor(c sdl.Color) uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
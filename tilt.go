@@ -0,0 +1,36 @@
+package main
+
+// Tilt steers with the motion sensors of controllers like the DualSense or Switch Pro.  SDL 1.2 has
+// no sensor API, but on Linux the kernel gives these controllers a second "Motion Sensors" event
+// device whose first two axes are the tilt.  Opened with -evdev (or seen by SDL as a joystick), that
+// device is named with -tilt.  Its axes are measured from where they were when last recentered and
+// scaled by the sensitivity, so a small tilt is enough to move.
+type Tilt struct {
+	zero map[int]int16 // axis values that count as level
+	last map[int]int16 // the latest axis values
+}
+
+// Create a Tilt that is level at 0
+func NewTilt() *Tilt {
+	return &Tilt{zero: make(map[int]int16), last: make(map[int]int16)}
+}
+
+// Apply gives the tilt axis value as a stick position
+func (t *Tilt) Apply(axis int, v int16) int16 {
+	t.last[axis] = v
+	out := float64(int(v)-int(t.zero[axis])) * config.TiltSensitivity
+	if out > 32767 {
+		out = 32767
+	}
+	if out < -32768 {
+		out = -32768
+	}
+	return int16(out)
+}
+
+// Recenter makes the way the controller is held now count as level
+func (t *Tilt) Recenter() {
+	for axis, v := range t.last {
+		t.zero[axis] = v
+	}
+}
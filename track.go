@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+)
+
+const (
+	// a marker this close (in pixels) to the dot is on target
+	TRACK_RADIUS = 40
+	// size of the dot
+	TRACK_DOT = 16
+	// color of the dot, and of the area around it that counts as on target
+	TRACK_COLOR = 0x00ffffff
+	TRACK_AREA  = 0x00606060
+	// how far from the screen edges the dot keeps
+	TRACK_MARGIN = 80
+)
+
+// A Tracker is the moving dot of the pursuit tracking exercise and how well each player kept up
+// with it
+type Tracker struct {
+	X, Y    int
+	Samples int       // updates so far
+	Close   []int     // for each player, the updates they were within TRACK_RADIUS of the dot
+	Error   []float64 // for each player, their distance from the dot summed over the updates
+}
+
+// updateTrack moves the dot along its path and measures how far each player is from it.  The path
+// is a Lissajous curve, smooth and never quite repeating the same loop.
+func (g *Game) updateTrack() {
+	t := g.track
+	if t == nil {
+		t = &Tracker{}
+		g.track = t
+	}
+	secs := g.Clock.Seconds() * config.TrackSpeed
+	w, h := worldSize()
+	t.X = w/2 + int(float64(w/2-TRACK_MARGIN)*math.Sin(secs*0.7))
+	t.Y = h/2 + int(float64(h/2-TRACK_MARGIN)*math.Sin(secs*1.1+math.Pi/4))
+	for len(t.Close) < len(g.Markers) {
+		t.Close = append(t.Close, 0)
+		t.Error = append(t.Error, 0)
+	}
+	t.Samples++
+	for i := range g.Markers {
+		d := math.Hypot(float64(g.Markers[i].X-t.X), float64(g.Markers[i].Y-t.Y))
+		t.Error[i] += d
+		if d <= TRACK_RADIUS {
+			t.Close[i]++
+		}
+	}
+	if config.TrackTime > 0 && g.Clock >= config.TrackTime {
+		g.Won = true
+		g.event("won")
+	}
+}
+
+// Accuracy gives the percentage of the time player i was on target
+func (t *Tracker) Accuracy(i int) float64 {
+	if t == nil || t.Samples == 0 || i >= len(t.Close) {
+		return 0
+	}
+	return 100 * float64(t.Close[i]) / float64(t.Samples)
+}
+
+// MeanError gives player i's average distance (in pixels) from the dot
+func (t *Tracker) MeanError(i int) float64 {
+	if t == nil || t.Samples == 0 || i >= len(t.Error) {
+		return 0
+	}
+	return t.Error[i] / float64(t.Samples)
+}
+
+// Draw the dot and the area around it that counts as on target
+func (t *Tracker) Draw(screen *sdl.Surface) {
+	Outline{R: *t.Rect(), Color: TRACK_AREA, Width: 2}.Draw(screen)
+	screen.FillRect(&sdl.Rect{int16(t.X - TRACK_DOT/2), int16(t.Y - TRACK_DOT/2), TRACK_DOT, TRACK_DOT}, TRACK_COLOR)
+}
+
+// Get the area around the dot that counts as on target
+func (t *Tracker) Rect() *sdl.Rect {
+	return &sdl.Rect{int16(t.X - TRACK_RADIUS), int16(t.Y - TRACK_RADIUS), 2 * TRACK_RADIUS, 2 * TRACK_RADIUS}
+}
+
+// TrackDot gives the dot to draw in tracking mode, or nil
+func (g *Game) TrackDot() Drawable {
+	if g.track == nil {
+		return nil
+	}
+	return g.track
+}
+
+// trackText gives each player's accuracy so far and how long is left
+func trackText(g *Game) string {
+	text := ""
+	for i := range g.Markers {
+		text += fmt.Sprintf("P%d: %.0f%%    ", i+1, g.track.Accuracy(i))
+	}
+	if config.TrackTime > 0 {
+		left := config.TrackTime - g.Clock
+		if left < 0 {
+			left = 0
+		}
+		text += fmt.Sprintf("%d:%02d left", int(left.Minutes()), int(left.Seconds())%60)
+	}
+	return text
+}
+
+// trackResults gives how well everybody kept up with the dot, for the results screen
+func trackResults(g *Game) (title string, lines []string) {
+	for i := range g.Markers {
+		lines = append(lines, fmt.Sprintf("P%d: on target %.0f%% of the time, %.0f pixels off on average", i+1, g.track.Accuracy(i), g.track.MeanError(i)))
+	}
+	return "Time's up!", lines
+}
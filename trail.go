@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/jonhanks/Go-SDL/sdl"
+)
+
+const (
+	// most positions a trail can remember
+	TRAIL_MAX = 240
+	// size of the dots a trail is drawn with, at WIDTH x HEIGHT
+	TRAIL_SIZE = RWIDTH / 2
+	// how opaque the newest dot of a trail is
+	TRAIL_ALPHA = 160
+)
+
+// A Trail remembers where a marker has recently been, in a ring buffer of the last config.Trail
+// positions
+type Trail struct {
+	pos  [TRAIL_MAX][2]int16
+	next int // where the next position goes
+	n    int // how many positions there are
+}
+
+// add remembers a position, forgetting the oldest one if the trail is full
+func (t *Trail) add(x, y int) {
+	size := config.Trail
+	t.pos[t.next] = [2]int16{int16(x), int16(y)}
+	t.next = (t.next + 1) % size
+	if t.n < size {
+		t.n++
+	}
+}
+
+// at gives the i'th position of the trail, 0 being the oldest
+func (t *Trail) at(i int) (x, y int) {
+	size := config.Trail
+	p := t.pos[(t.next-t.n+i+size)%size]
+	return int(p[0]), int(p[1])
+}
+
+// A TrailDrawable draws a marker's trail as dots that fade out towards the oldest
+type TrailDrawable struct {
+	Trail *Trail
+	Color uint32
+}
+
+// Trail gives the marker's trail to draw
+func (m *Marker) Trail() TrailDrawable {
+	return TrailDrawable{&m.trail, m.Color}
+}
+
+// Get the bounding rectangle of the trail, the whole playfield
+func (t TrailDrawable) Rect() *sdl.Rect {
+	w, h := worldSize()
+	return &sdl.Rect{0, 0, uint16(w), uint16(h)}
+}
+
+// Draw the trail.  SDL 1.2 cannot fill with alpha, so a dot sized surface is blitted with a
+// different surface alpha for each position.
+func (t TrailDrawable) Draw(screen *sdl.Surface) {
+	if t.Trail.n == 0 {
+		return
+	}
+	size := scaled(TRAIL_SIZE)
+	dot := sdl.CreateRGBSurface(sdl.SWSURFACE, size, size, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0)
+	if dot == nil {
+		return
+	}
+	defer dot.Free()
+	dot.FillRect(nil, t.Color)
+	for i := 0; i < t.Trail.n; i++ {
+		x, y := t.Trail.at(i)
+		dot.SetAlpha(sdl.SRCALPHA, uint8(TRAIL_ALPHA*(i+1)/t.Trail.n))
+		screen.Blit(&sdl.Rect{int16(x - size/2), int16(y - size/2), 0, 0}, dot, nil)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"image"
+	"image/color/palette"
+	imgdraw "image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// a GIF is kept in memory until the end, so it stops growing after this many frames
+	VIDEO_MAX_FRAMES = 1800
+	// frames waiting to be written, more than this and frames are dropped rather than slowing the game
+	VIDEO_QUEUE = 8
+)
+
+// A Video records what is on the screen during play, for sharing how a player is getting on.  A path
+// ending in .gif makes an animated GIF, anything else is a directory that gets a PNG for each frame.
+// Frames are taken at most fps times a second and shrunk, and are written out away from the main
+// loop so the game does not slow down.
+type Video struct {
+	path   string
+	every  time.Duration // the least time between frames
+	shrink int
+	last   time.Time // when the last frame was taken
+	frames chan videoFrame
+	done   chan error
+}
+
+// a videoFrame is the screen at a moment
+type videoFrame struct {
+	img *image.RGBA
+	at  time.Time
+}
+
+// Start recording a video to path
+func NewVideo(path string, fps, shrink int) (*Video, error) {
+	isGIF := strings.EqualFold(filepath.Ext(path), ".gif")
+	if !isGIF {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, err
+		}
+	}
+	v := &Video{path: path, every: time.Second / time.Duration(fps), shrink: shrink, frames: make(chan videoFrame, VIDEO_QUEUE), done: make(chan error, 1)}
+	if isGIF {
+		go v.writeGIF()
+	} else {
+		go v.writePNGs()
+	}
+	return v, nil
+}
+
+// Frame takes the screen as a frame, if it has been long enough since the last one.  Nothing is drawn
+// while everything is still, so frames come as the screen changes and not evenly.
+func (v *Video) Frame(screen *sdl.Surface, now time.Time) {
+	if now.Sub(v.last) < v.every {
+		return
+	}
+	v.last = now
+	select {
+	case v.frames <- videoFrame{surfaceImage(screen, v.shrink), now}:
+	default:
+		// the writer is behind, skip this frame
+	}
+}
+
+// writePNGs saves each frame to the directory, numbered in order
+func (v *Video) writePNGs() {
+	var err error
+	n := 0
+	for f := range v.frames {
+		if err != nil {
+			continue
+		}
+		var out *os.File
+		if out, err = os.Create(filepath.Join(v.path, fmt.Sprintf("frame-%06d.png", n))); err != nil {
+			continue
+		}
+		if err = png.Encode(out, f.img); err != nil {
+			out.Close()
+			continue
+		}
+		err = out.Close()
+		n++
+	}
+	v.done <- err
+}
+
+// writeGIF gathers the frames and writes them as a GIF once recording stops.  Each frame is shown
+// until the time the next was taken.
+func (v *Video) writeGIF() {
+	anim := &gif.GIF{}
+	var last time.Time
+	for f := range v.frames {
+		if len(anim.Image) >= VIDEO_MAX_FRAMES {
+			continue
+		}
+		if n := len(anim.Delay); n > 0 {
+			anim.Delay[n-1] = gifDelay(f.at.Sub(last))
+		}
+		last = f.at
+		p := image.NewPaletted(f.img.Rect, palette.Plan9)
+		imgdraw.Draw(p, p.Rect, f.img, image.Point{}, imgdraw.Src)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, gifDelay(v.every))
+	}
+	out, err := os.Create(v.path)
+	if err != nil {
+		v.done <- err
+		return
+	}
+	if err = gif.EncodeAll(out, anim); err != nil {
+		out.Close()
+		v.done <- err
+		return
+	}
+	v.done <- out.Close()
+}
+
+// gifDelay gives d in the hundredths of a second GIF frame times are in, at least one
+func gifDelay(d time.Duration) int {
+	if h := int(d / (10 * time.Millisecond)); h > 1 {
+		return h
+	}
+	return 1
+}
+
+// Close stops recording and waits for the frames to be written
+func (v *Video) Close() error {
+	close(v.frames)
+	return <-v.done
+}
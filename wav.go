@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// A Wave holds 16 bit PCM sound data
+type Wave struct {
+	Channels   int
+	SampleRate int
+	Samples    []int16 // interleaved when there is more than one channel
+}
+
+// readWAV loads a 16 bit PCM WAV file
+func readWAV(path string) (*Wave, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New(path + ": not a WAV file")
+	}
+	w := &Wave{}
+	bits := 0
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8:]
+		if size > len(body) {
+			size = len(body)
+		}
+		body = body[:size]
+		switch id {
+		case "fmt ":
+			if size < 16 || binary.LittleEndian.Uint16(body[0:2]) != 1 {
+				return nil, errors.New(path + ": only PCM WAV files are supported")
+			}
+			w.Channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			w.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			bits = int(binary.LittleEndian.Uint16(body[14:16]))
+		case "data":
+			if bits != 16 || w.Channels < 1 {
+				return nil, errors.New(path + ": only 16 bit WAV files are supported")
+			}
+			w.Samples = make([]int16, size/2)
+			binary.Read(bytes.NewReader(body[:len(w.Samples)*2]), binary.LittleEndian, w.Samples)
+			return w, nil
+		}
+		// chunks are padded to an even size
+		pos += 8 + size + size%2
+	}
+	return nil, errors.New(path + ": no sound data")
+}
+
+// Resample returns the sound played faster by ratio, which raises its pitch by the same ratio (and
+// shortens it).  Samples are linearly interpolated.
+func (w *Wave) Resample(ratio float64) *Wave {
+	frames := len(w.Samples) / w.Channels
+	n := int(float64(frames) / ratio)
+	out := &Wave{Channels: w.Channels, SampleRate: w.SampleRate, Samples: make([]int16, n*w.Channels)}
+	for i := 0; i < n; i++ {
+		pos := float64(i) * ratio
+		a := int(pos)
+		frac := pos - float64(a)
+		b := a + 1
+		if b >= frames {
+			b = frames - 1
+		}
+		for c := 0; c < w.Channels; c++ {
+			sa, sb := float64(w.Samples[a*w.Channels+c]), float64(w.Samples[b*w.Channels+c])
+			out.Samples[i*w.Channels+c] = int16(sa + (sb-sa)*frac)
+		}
+	}
+	return out
+}
+
+// Write the sound as a 16 bit PCM WAV file
+func (w *Wave) Write(out io.Writer) error {
+	dataSize := uint32(len(w.Samples) * 2)
+	blockAlign := uint16(w.Channels * 2)
+	header := []interface{}{
+		[]byte("RIFF"), 36 + dataSize, []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(w.Channels), uint32(w.SampleRate),
+		uint32(w.SampleRate) * uint32(blockAlign), blockAlign, uint16(16),
+		[]byte("data"), dataSize,
+	}
+	for _, v := range header {
+		if err := binary.Write(out, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	return binary.Write(out, binary.LittleEndian, w.Samples)
+}
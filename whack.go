@@ -0,0 +1,87 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Game modes
+const (
+	MODE_ALPHABET = "alphabet" // collect the letters in order
+	MODE_ORDERED  = "ordered"  // several letters are shown, only the next one in order can be collected
+	MODE_WHACK    = "whack"    // whack-a-mole, reach each goal before it times out
+	MODE_FREE     = "free"     // all the letters are shown and can be collected in any order
+	MODE_MAZE     = "maze"     // collect the letters in order around the walls of a maze (-maze)
+	MODE_RACE     = "race"     // every player has their own letters, the first to collect them all wins
+	MODE_PAINT    = "paint"    // no goals, the markers paint trails on the screen
+	MODE_SIMON    = "simon"    // simon says, visit the goals in the order they lit up
+	MODE_TRACK    = "track"    // keep the marker on a dot moving around the screen
+)
+
+// the most points a goal is worth in whack mode, when it is reached instantly
+const WHACK_POINTS = 10
+
+// spawnWhack hides the current goal and moves it to a new grid cell.  It is revealed once the spawn
+// interval has passed.
+func (g *Game) spawnWhack() {
+	goal := g.Goals[g.CurGoal]
+	placeOnGrid(goal, config.GridCols, config.GridRows, &g.recent, g.rng)
+	goal.Hidden = config.SpawnInterval > 0
+	g.goalShown = g.Clock + config.SpawnInterval
+}
+
+// updateWhack reveals the current goal when its spawn interval is over.  When it has been shown for
+// longer than the goal timeout it vanishes, to pop up somewhere else after the spawn interval.
+func (g *Game) updateWhack() {
+	goal := g.Goals[g.CurGoal]
+	if g.Clock < g.goalShown {
+		return
+	}
+	goal.Hidden = false
+	if config.GoalTimeout <= 0 || g.Clock-g.goalShown < config.GoalTimeout {
+		return
+	}
+	// nobody got to it in time
+	g.Misses++
+	if config.MissPenalty {
+		for i := range g.Markers {
+			if g.Markers[i].Score > 0 {
+				g.Markers[i].Score--
+			}
+		}
+	}
+	g.spawnWhack()
+}
+
+// whackPoints gives the score for reaching a goal after it was shown for elapsed.  Faster is better,
+// but reaching it always earns at least a point.
+func whackPoints(elapsed, timeout time.Duration) int {
+	if timeout <= 0 || elapsed >= timeout {
+		return 1
+	}
+	bonus := int(float64(WHACK_POINTS-1) * float64(timeout-elapsed) / float64(timeout))
+	return 1 + bonus
+}
+
+// placeOnGrid centers the goal in a random cell of a cols x rows grid over the screen.  The goal is
+// always moved to a different cell than it was in, and kept away from the recent positions in h.
+func placeOnGrid(goal *Goal, cols, rows int, h *placeHistory, rng *rand.Rand) {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	width, height := worldSize()
+	cellW, cellH := width/cols, height/rows
+	cur := (goal.Y/cellH)*cols + goal.X/cellW
+	place(goal, h, func() (int, int) {
+		cell := rng.Intn(cols * rows)
+		if cols*rows > 1 {
+			for cell == cur {
+				cell = rng.Intn(cols * rows)
+			}
+		}
+		return (cell%cols)*cellW + cellW/2, (cell/cols)*cellH + cellH/2
+	})
+}
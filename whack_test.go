@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWhackTimeout(t *testing.T) {
+	useConfig(t, Config{Mode: MODE_WHACK, GridCols: 4, GridRows: 3, SpawnInterval: time.Second / 2, GoalTimeout: 2 * time.Second, MissPenalty: true})
+	goal := &Goal{W: 20, H: 20}
+	g := NewGame([]Marker{{Score: 5}}, []*Goal{goal})
+	g.showGoal()
+	x, y := goal.X, goal.Y
+
+	// the clock is checked at each of these times in turn
+	tests := []struct {
+		clock      time.Duration
+		wantHidden bool
+		wantMisses int
+		wantMoved  bool // the goal moved since the last check
+	}{
+		{0, true, 0, false},
+		{time.Second / 4, true, 0, false},
+		{time.Second / 2, false, 0, false},
+		{2 * time.Second, false, 0, false},
+		// shown at 0.5s, so it times out at 2.5s and pops up elsewhere half a second later
+		{5 * time.Second / 2, true, 1, true},
+		{3 * time.Second, false, 1, false},
+		{5 * time.Second, true, 2, true},
+	}
+	for _, tt := range tests {
+		g.Clock = tt.clock
+		g.updateWhack()
+		moved := goal.X != x || goal.Y != y
+		x, y = goal.X, goal.Y
+		if goal.Hidden != tt.wantHidden || g.Misses != tt.wantMisses || moved != tt.wantMoved {
+			t.Errorf("at %v: hidden %v misses %d moved %v, want %v %d %v", tt.clock, goal.Hidden, g.Misses, moved, tt.wantHidden, tt.wantMisses, tt.wantMoved)
+		}
+	}
+	if g.Markers[0].Score != 3 {
+		t.Errorf("score = %d after two misses, want 3", g.Markers[0].Score)
+	}
+}
+
+func TestWhackPoints(t *testing.T) {
+	tests := []struct {
+		elapsed, timeout time.Duration
+		want             int
+	}{
+		{0, time.Second, WHACK_POINTS},
+		{time.Second / 2, time.Second, 1 + (WHACK_POINTS-1)/2},
+		{time.Second, time.Second, 1},
+		{2 * time.Second, time.Second, 1},
+		{time.Second, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := whackPoints(tt.elapsed, tt.timeout); got != tt.want {
+			t.Errorf("whackPoints(%v, %v) = %d, want %d", tt.elapsed, tt.timeout, got, tt.want)
+		}
+	}
+}
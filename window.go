@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"github.com/jonhanks/Go-SDL/sdl"
+	"math"
+)
+
+// The game is laid out for a WIDTH x HEIGHT screen.  The frame it draws is the size of the screen it
+// opens on instead, so on a bigger display (with -fullscreen) the fonts, markers and their steps are
+// drawn bigger by frameScale rather than the whole frame being stretched.
+var (
+	frameW, frameH   = WIDTH, HEIGHT
+	frameScale       = 1.0
+	markerW, markerH = RWIDTH, RHEIGHT
+)
+
+// setFrameSize makes the frame width x height and scales the sizes of things to it, by whichever of
+// width and height grew less
+func setFrameSize(width, height int) {
+	frameW, frameH = width, height
+	frameScale = math.Min(float64(width)/WIDTH, float64(height)/HEIGHT)
+	markerW, markerH = scaled(RWIDTH), scaled(RHEIGHT)
+}
+
+// scaled gives a size laid out for WIDTH x HEIGHT at the frame's scale
+func scaled(size int) int {
+	return int(math.Round(float64(size) * frameScale))
+}
+
+// frameRect gives the whole frame
+func frameRect() *sdl.Rect {
+	return &sdl.Rect{0, 0, uint16(frameW), uint16(frameH)}
+}
+
+// A Window is the video surface the game is shown in.  The game draws a frame the size the window
+// opened at.  Once the window has been resized to anything else the frame is drawn off screen and
+// scaled up or down to fit the window, keeping its shape, with black bars along the sides that do not
+// fit.
+type Window struct {
+	Video      *sdl.Surface // the real screen
+	Fullscreen bool
+	frame      *sdl.Surface // the frame, when it is not drawn straight onto Video
+	flags      uint32
+	sync       uint32 // flags asking for Flip to wait for the display's refresh, with vsync
+	native     [2]int // the size of the display
+	windowed   [2]int // the size of the window, to go back to from fullscreen
+}
+
+// OpenWindow opens a WIDTH x HEIGHT window that can be resized, or covers the display with the game
+// if fullscreen is set, and makes the frame the size it opened at.  With vsync the window is double buffered in video memory, so flipping waits
+// for the display's refresh where the video driver can do that.
+func OpenWindow(fullscreen, vsync bool) (*Window, error) {
+	w := &Window{flags: sdl.RESIZABLE, windowed: [2]int{WIDTH, HEIGHT}}
+	if vsync {
+		w.sync = sdl.HWSURFACE | sdl.DOUBLEBUF
+	}
+	// before a video mode is set this gives the desktop's
+	info := sdl.GetVideoInfo()
+	w.native = [2]int{int(info.Current_w), int(info.Current_h)}
+	if w.native[0] <= 0 || w.native[1] <= 0 {
+		w.native = w.windowed
+	}
+	if fullscreen {
+		setFrameSize(w.native[0], w.native[1])
+		if err := w.ToggleFullscreen(); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	if err := w.Resize(WIDTH, HEIGHT); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ToggleFullscreen switches between covering the display at its own resolution and a window of the
+// size the window last had
+func (w *Window) ToggleFullscreen() error {
+	if w.Fullscreen {
+		w.Fullscreen, w.flags = false, sdl.RESIZABLE
+		return w.Resize(w.windowed[0], w.windowed[1])
+	}
+	if w.Video != nil {
+		w.windowed = [2]int{int(w.Video.W), int(w.Video.H)}
+	}
+	w.Fullscreen, w.flags = true, sdl.FULLSCREEN
+	return w.Resize(w.native[0], w.native[1])
+}
+
+// Resize changes the window to width x height
+func (w *Window) Resize(width, height int) error {
+	video := sdl.SetVideoMode(width, height, 32, w.flags|w.sync)
+	if video == nil {
+		return errors.New(sdl.GetError())
+	}
+	w.Video = video
+	if width == frameW && height == frameH {
+		w.Free()
+	} else if w.frame == nil {
+		w.frame = sdl.CreateRGBSurface(sdl.SWSURFACE, frameW, frameH, 32, 0x00ff0000, 0x0000ff00, 0x000000ff, 0)
+	}
+	return nil
+}
+
+// Screen gives the surface to draw the frame on
+func (w *Window) Screen() *sdl.Surface {
+	if w.frame != nil {
+		return w.frame
+	}
+	return w.Video
+}
+
+// Flip shows the frame in the window, scaling it if the window is another size
+func (w *Window) Flip() {
+	if w.frame != nil {
+		view := w.View()
+		if int(view.W) != int(w.Video.W) || int(view.H) != int(w.Video.H) {
+			w.Video.FillRect(nil, 0)
+		}
+		stretchOnto(w.Video, view, w.frame)
+	}
+	w.Video.Flip()
+}
+
+// View gives the part of the window the frame is shown in, as big as fits without changing its shape
+func (w *Window) View() *sdl.Rect {
+	vw, vh := int(w.Video.W), int(w.Video.H)
+	if w.frame == nil {
+		return &sdl.Rect{0, 0, uint16(vw), uint16(vh)}
+	}
+	// scale by whichever of width and height is shorter
+	fw, fh := vw, frameH*vw/frameW
+	if fh > vh {
+		fw, fh = frameW*vh/frameH, vh
+	}
+	return &sdl.Rect{int16((vw - fw) / 2), int16((vh - fh) / 2), uint16(fw), uint16(fh)}
+}
+
+// Stretched reports whether the frame is drawn off screen and stretched to the window
+func (w *Window) Stretched() bool {
+	return w.frame != nil
+}
+
+// FlipRects shows just the given parts of the frame, which has to be drawn straight onto Video
+func (w *Window) FlipRects(rects []sdl.Rect) {
+	w.Video.UpdateRects(rects)
+}
+
+// ToFrame converts a position in the window, such as the mouse pointer's, to the frame
+func (w *Window) ToFrame(x, y int) (int, int) {
+	view := w.View()
+	if w.frame == nil || view.W == 0 || view.H == 0 {
+		return x, y
+	}
+	return (x - int(view.X)) * frameW / int(view.W), (y - int(view.Y)) * frameH / int(view.H)
+}
+
+// Free the off screen frame
+func (w *Window) Free() {
+	if w.frame != nil {
+		w.frame.Free()
+		w.frame = nil
+	}
+}
+
+// stretchOnto copies the 32 bit surface src over the part r of the 32 bit surface dst, nearest
+// neighbour.  Unlike scaleSurface it reuses dst, as this is done every frame.
+func stretchOnto(dst *sdl.Surface, r *sdl.Rect, src *sdl.Surface) {
+	dx, dy, dw, dh := int(r.X), int(r.Y), int(r.W), int(r.H)
+	src.Lock()
+	dst.Lock()
+	for y := 0; y < dh; y++ {
+		sy := y * int(src.H) / dh
+		for x := 0; x < dw; x++ {
+			*pixelPtr(dst, dx+x, dy+y) = pixelAt(src, x*int(src.W)/dw, sy)
+		}
+	}
+	dst.Unlock()
+	src.Unlock()
+}
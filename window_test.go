@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSetFrameSize(t *testing.T) {
+	t.Cleanup(func() { setFrameSize(WIDTH, HEIGHT) })
+	tests := []struct {
+		w, h      int
+		wantScale float64
+		wantW     int // marker width
+		wantFont  int // the goal font at its usual 60 points
+	}{
+		{WIDTH, HEIGHT, 1, RWIDTH, 60},
+		{2 * WIDTH, 2 * HEIGHT, 2, 2 * RWIDTH, 120},
+		// wider than the layout, so the height decides
+		{1920, 1080, 1.40625, 28, 84},
+		{800, 600, 0.78125, 16, 47},
+	}
+	for _, tt := range tests {
+		setFrameSize(tt.w, tt.h)
+		if frameW != tt.w || frameH != tt.h || frameScale != tt.wantScale || markerW != tt.wantW || markerH != tt.wantW || scaled(60) != tt.wantFont {
+			t.Errorf("%dx%d: frame %dx%d scale %v marker %dx%d font %d, want scale %v marker %d font %d", tt.w, tt.h, frameW, frameH, frameScale, markerW, markerH, scaled(60), tt.wantScale, tt.wantW, tt.wantFont)
+		}
+	}
+}
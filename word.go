@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadWords reads a word list, one word per line.  Blank lines and lines starting with # are skipped.
+func loadWords(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%s: no words", path)
+	}
+	return words, nil
+}
+
+// nextWord replaces the goals with the letters of the next word in config.Words, starting the lesson
+// over after the last word.
+func (g *Game) nextWord() {
+	if g.MakeGoals == nil {
+		return
+	}
+	g.Word++
+	if g.Word >= len(config.Words) {
+		g.Word = 0
+	}
+	for _, goal := range g.Goals {
+		goal.Free()
+	}
+	g.Goals = g.MakeGoals(config.Words[g.Word], g.rng)
+	g.goalsMade(g.Goals, len(g.Goals))
+}
+
+// WordProgress returns the word being spelled with the letters not yet collected blanked out
+func (g *Game) WordProgress() string {
+	if len(config.Words) == 0 {
+		return ""
+	}
+	return wordProgress(config.Words[g.Word], g.CurGoal)
+}
+
+// wordProgress shows the first collected letters of word and an underscore for each of the rest
+func wordProgress(word string, collected int) string {
+	letters := []rune(word)
+	out := make([]string, len(letters))
+	for i, ch := range letters {
+		if i < collected {
+			out[i] = string(ch)
+		} else {
+			out[i] = "_"
+		}
+	}
+	return strings.Join(out, " ")
+}
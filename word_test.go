@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordProgress(t *testing.T) {
+	tests := []struct {
+		word      string
+		collected int
+		want      string
+	}{
+		{"CAT", 0, "_ _ _"},
+		{"CAT", 1, "C _ _"},
+		{"CAT", 2, "C A _"},
+		{"CAT", 3, "C A T"},
+		{"CAT", 5, "C A T"},
+		{"ÉTÉ", 1, "É _ _"},
+	}
+	for _, tt := range tests {
+		if got := wordProgress(tt.word, tt.collected); got != tt.want {
+			t.Errorf("wordProgress(%q, %d) = %q, want %q", tt.word, tt.collected, got, tt.want)
+		}
+	}
+}
+
+// letterGoals makes a goal for each letter of src, in order
+func letterGoals(src string, rng *rand.Rand) []*Goal {
+	var goals []*Goal
+	for i, ch := range src {
+		goals = append(goals, &Goal{Text: string(ch), Order: i, X: 100 + 50*i, Y: 100, W: 30, H: 30})
+	}
+	return goals
+}
+
+func TestWordsInTurn(t *testing.T) {
+	useConfig(t, Config{Words: []string{"CAT", "DOG"}, EndPolicy: END_LOOP, CollectButton: -1})
+	g := NewGame([]Marker{{}}, letterGoals("CAT", nil))
+	g.MakeGoals = letterGoals
+	tests := []struct {
+		wantWord     string
+		wantProgress string
+	}{
+		{"DOG", "_ _ _"},
+		{"CAT", "_ _ _"},
+		{"DOG", "_ _ _"},
+	}
+	for _, tt := range tests {
+		for range g.Goals {
+			g.advance()
+		}
+		var word []string
+		for _, goal := range g.Goals {
+			word = append(word, goal.Text)
+		}
+		if got := strings.Join(word, ""); got != tt.wantWord || g.WordProgress() != tt.wantProgress {
+			t.Errorf("after a word: spelling %q with %q, want %q with %q", got, g.WordProgress(), tt.wantWord, tt.wantProgress)
+		}
+	}
+}
+
+func TestLoadWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# animals\ncat\n\n  dog  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	words, err := loadWords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cat", "dog"}; !reflect.DeepEqual(words, want) {
+		t.Errorf("loadWords = %q, want %q", words, want)
+	}
+	if err := os.WriteFile(path, []byte("# nothing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadWords(path); err == nil {
+		t.Errorf("loadWords of an empty list did not fail")
+	}
+}